@@ -1,5 +1,10 @@
-data "beeswax_role" "example" {
-  # Limitation: it's only possible to retrieve the role by ID for now
-  # TODO: allow finding role by name
+# Look up a role by ID
+data "beeswax_role" "by_id" {
   id = 42
 }
+
+# Or by exact name, the same configuration works on every buzz
+data "beeswax_role" "by_name" {
+  name             = "my_role"
+  exclude_archived = true
+}
@@ -19,3 +19,16 @@ resource "beeswax_user" "user" {
   all_account_access = false
   account_group_ids  = []
 }
+
+# Only active roles whose name starts with "team_"
+data "beeswax_roles" "teams" {
+  name_regex = "^team_"
+  archived   = false
+}
+
+output "team_permissions" {
+  value = {
+    for role in data.beeswax_roles.teams.roles :
+    role.name => role.permissions
+  }
+}
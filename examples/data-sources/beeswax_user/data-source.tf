@@ -1,5 +1,9 @@
-data "beeswax_user" "example" {
-  # Limitation: it's only possible to retrieve the user by ID for now.
-  # TODO: allow finding user by email
+# Look up a user by ID
+data "beeswax_user" "by_id" {
   id = 42
 }
+
+# Or by email
+data "beeswax_user" "by_email" {
+  email = "myemail@myorg.com"
+}
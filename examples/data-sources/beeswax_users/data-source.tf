@@ -0,0 +1,15 @@
+# Every active super user of the buzz
+data "beeswax_users" "super_users" {
+  active     = true
+  super_user = true
+}
+
+output "super_user_emails" {
+  value = [for user in data.beeswax_users.super_users.users : user.email]
+}
+
+# Users of a given role and email domain
+data "beeswax_users" "myorg_admins" {
+  role_id      = 1
+  email_domain = "myorg.com"
+}
@@ -1 +1,5 @@
-terraform import beeswax_role.example 42
\ No newline at end of file
+# Import by numeric ID
+terraform import beeswax_role.example 42
+
+# Import by exact name, fails if several roles share the name
+terraform import beeswax_role.example my_role
@@ -8,7 +8,9 @@ resource "beeswax_role" "example" {
     },
     {
       object_type = "creative"
-      permission  = 13
+      read        = true
+      update      = true
+      delete      = true
     },
     {
       object_type = "static"
@@ -1 +1,5 @@
-terraform import beeswax_user.example 42
\ No newline at end of file
+# Import by numeric ID
+terraform import beeswax_user.example 42
+
+# Import by email
+terraform import beeswax_user.example myemail@myorg.com
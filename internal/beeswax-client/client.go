@@ -2,14 +2,25 @@ package beeswax
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
+// DefaultRequestTimeout bounds a single HTTP attempt when the caller's context has no earlier deadline.
+const DefaultRequestTimeout = 60 * time.Second
+
 type User struct {
 	ID               int64   `json:"id"`
 	SuperUser        bool    `json:"super_user"`
@@ -17,7 +28,7 @@ type User struct {
 	FirstName        string  `json:"first_name"`
 	LastName         string  `json:"last_name"`
 	RoleID           int64   `json:"role_id"`
-	AccountID        int64   `json:"account_id"`
+	AccountID        *int64  `json:"account_id,omitempty"` // nil when the user has no account
 	Active           bool    `json:"active"`
 	AllAccountAccess bool    `json:"all_account_access"`
 	AccountGroupIDs  []int64 `json:"account_group_ids"`
@@ -26,7 +37,7 @@ type User struct {
 type Role struct {
 	ID                   int64        `json:"id"`
 	Name                 string       `json:"name"`
-	ParentRoleID         int64        `json:"parent_role_id"`
+	ParentRoleID         *int64       `json:"parent_role_id,omitempty"` // nil when the role has no parent
 	Archived             bool         `json:"archived"`
 	Notes                string       `json:"notes"`
 	SharedAcrossAccounts bool         `json:"shared_across_accounts"`
@@ -40,140 +51,274 @@ type Permission struct {
 }
 
 type Client struct {
-	APIURL   string
+	APIURL string
+	// RequestTimeout is the deadline applied to each HTTP attempt on top of the caller's context. Zero disables it.
+	RequestTimeout time.Duration
+	// Retry decides which failed calls are attempted again and how long to wait in between.
+	Retry RetryPolicy
+	// PageSize is the number of objects requested per page by list calls. Zero uses DefaultPageSize.
+	PageSize int
 	email    string
 	password string
-	client   *http.Client
+	// token replaces the email and password session login when set, see NewTokenClient
+	token  string
+	client *http.Client
+
+	// loginMu serializes logins; session counts successful ones so parallel calls
+	// hitting an expired session trigger a single re-authentication.
+	loginMu sync.Mutex
+	session atomic.Uint64
+
+	// limiter and slots throttle requests, see SetLimits
+	limiter *rate.Limiter
+	slots   chan struct{}
 }
 
-func NewClient(apiURL, email, password string) *Client {
+func NewClient(apiURL, email, password string, options Options) (*Client, error) {
+	transport, err := options.transport()
+	if err != nil {
+		return nil, err
+	}
+	timeout := DefaultRequestTimeout
+	if options.RequestTimeout > 0 {
+		timeout = options.RequestTimeout
+	}
 	jar, _ := cookiejar.New(nil)
 	return &Client{
-		APIURL:   apiURL,
-		email:    email,
-		password: password,
+		APIURL:         apiURL,
+		RequestTimeout: timeout,
+		Retry:          DefaultRetryPolicy(),
+		email:          email,
+		password:       password,
 		client: &http.Client{
-			Jar: jar, // will keep the cookie to stay logged in
+			Jar:       jar, // will keep the cookie to stay logged in
+			Transport: transport,
 		},
+	}, nil
+}
+
+// NewTokenClient returns a client authenticating every request with an API token, or a pre-issued
+// session token, sent as a bearer Authorization header instead of logging in with an email and password.
+func NewTokenClient(apiURL, token string, options Options) (*Client, error) {
+	bx, err := NewClient(apiURL, "", "", options)
+	if err != nil {
+		return nil, err
 	}
+	bx.token = token
+	return bx, nil
 }
 
-func (bx *Client) Login() error {
+// withTimeout derives the per-attempt context so a hung endpoint cannot block forever.
+func (bx *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if bx.RequestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, bx.RequestTimeout)
+}
+
+// Login opens a session with the client email and password. It does nothing for a token client.
+func (bx *Client) Login(ctx context.Context) error {
+	if bx.token != "" {
+		return nil
+	}
+	bx.loginMu.Lock()
+	defer bx.loginMu.Unlock()
+	return bx.login(ctx)
+}
+
+// relogin renews the session, unless another call already did so since the given session was observed.
+func (bx *Client) relogin(ctx context.Context, seen uint64) error {
+	bx.loginMu.Lock()
+	defer bx.loginMu.Unlock()
+	if bx.session.Load() != seen {
+		return nil
+	}
+	return bx.login(ctx)
+}
+
+func (bx *Client) login(ctx context.Context) error {
+	if bx.APIURL == "" || bx.email == "" || bx.password == "" {
+		return errors.New("login failed: the Beeswax host, email and password must be known before calling the API")
+	}
 	loginPayload, err := json.Marshal(map[string]string{"email": bx.email, "password": bx.password})
 	if err != nil {
 		return err
 	}
-	resp, err := bx.client.Post(bx.APIURL+"/rest/v2/authenticate", "application/json", bytes.NewBuffer(loginPayload))
+	resp, body, err := bx.send(ctx, "POST", "/rest/v2/authenticate", loginPayload)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("login failed")
+		return fmt.Errorf("login failed: %w", newAPIError("POST", "/rest/v2/authenticate", resp.StatusCode, body))
 	}
 	// Authentication cookie is stored in the client
+	bx.session.Add(1)
 	return nil
 }
 
-func (bx *Client) request(method, path string, data interface{}) ([]byte, error) {
+func (bx *Client) request(ctx context.Context, method, path string, data interface{}) ([]byte, error) {
 	dataPayload, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("can't unmarshall: %w", err)
 	}
 
-	req, err := http.NewRequest(method, bx.APIURL+path, bytes.NewBuffer(dataPayload))
-	if err != nil {
-		return nil, fmt.Errorf("request creation failed: %w", err)
+	// Token clients never log in, check the host they would otherwise find missing at login
+	if bx.APIURL == "" {
+		return nil, errors.New("request failed: the Beeswax host must be known before calling the API")
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := bx.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+	// Log in lazily on the first call, so configuring the client needs no network access
+	session := bx.session.Load()
+	if session == 0 && bx.token == "" {
+		if err := bx.relogin(ctx, session); err != nil {
+			return nil, err
+		}
+		session = bx.session.Load()
 	}
-	defer resp.Body.Close()
-	bodyStr, err := io.ReadAll(resp.Body)
+	resp, bodyStr, err := bx.send(ctx, method, path, dataPayload)
 	if err != nil {
-		return nil, fmt.Errorf("can't read body response: %w", err)
+		return nil, err
+	}
+
+	// The session cookie expired: log in again once and replay the request
+	if resp.StatusCode == http.StatusUnauthorized && bx.token == "" {
+		if err := bx.relogin(ctx, session); err != nil {
+			return nil, fmt.Errorf("re-authentication failed: %w", err)
+		}
+		resp, bodyStr, err = bx.send(ctx, method, path, dataPayload)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Manage error responses
 	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
-		return bodyStr, errors.New("response " + resp.Status + " instead. API response: " + string(bodyStr))
+		return bodyStr, newAPIError(method, path, resp.StatusCode, bodyStr)
 	}
 
 	return bodyStr, nil
 }
 
-func (bx *Client) GetUser(userID int64) (User, error) {
-	response, err := bx.request("GET", fmt.Sprintf("/rest/v2/users/%d", userID), "")
-	if err != nil {
-		return User{}, err
+// send performs the HTTP exchange, retrying it according to bx.Retry.
+// The returned response body is already read and closed.
+func (bx *Client) send(ctx context.Context, method, path string, payload []byte) (*http.Response, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		resp, body, err := bx.do(ctx, method, path, payload)
+		if attempt >= bx.Retry.attempts() || !bx.Retry.shouldRetry(method, resp, err) || ctx.Err() != nil {
+			return resp, body, err
+		}
+		if err := sleep(ctx, bx.Retry.delay(attempt, resp)); err != nil {
+			return nil, nil, fmt.Errorf("request failed: %w", err)
+		}
 	}
-	user := User{}
-	err = json.Unmarshal(response, &user)
-	return user, err
 }
 
-func (bx *Client) CreateUser(user User) (int64, error) {
-	response, err := bx.request("POST", "/rest/v2/users", user)
+// do performs a single HTTP attempt.
+func (bx *Client) do(ctx context.Context, method, path string, payload []byte) (*http.Response, []byte, error) {
+	release, err := bx.acquire(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer release()
+
+	ctx, cancel := bx.withTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, bx.APIURL+path, bytes.NewReader(payload))
 	if err != nil {
-		return 0, err
+		return nil, nil, fmt.Errorf("request creation failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if bx.token != "" {
+		req.Header.Set("Authorization", "Bearer "+bx.token)
 	}
-	createdUser := User{}
-	err = json.Unmarshal(response, &createdUser)
-	return createdUser.ID, err
+
+	start := time.Now()
+	resp, err := bx.client.Do(req)
+	if err != nil {
+		logExchange(ctx, req, payload, nil, nil, time.Since(start), err)
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	logExchange(ctx, req, payload, resp, body, time.Since(start), err)
+	if err != nil {
+		return resp, nil, fmt.Errorf("can't read body response: %w", err)
+	}
+	return resp, body, nil
 }
 
-func (bx *Client) UpdateUser(user User) error {
-	_, err := bx.request("PUT", fmt.Sprintf("/rest/v2/users/%d", user.ID), user)
-	return err
+// Users gives access to the /rest/v2/users endpoint.
+func (bx *Client) Users() Resource[User] {
+	return NewResource[User](bx, "/rest/v2/users")
 }
 
-func (bx *Client) DeleteUser(userID int64) error {
-	_, err := bx.request("DELETE", fmt.Sprintf("/rest/v2/users/%d", userID), "")
-	return err
+// UserFilter narrows the users returned by GetUsers. Empty fields are ignored.
+type UserFilter struct {
+	Email     string
+	RoleID    int64
+	AccountID int64
+	Active    *bool
+	SuperUser *bool
 }
 
-func (bx *Client) GetRole(roleID int64) (Role, error) {
-	response, err := bx.request("GET", fmt.Sprintf("/rest/v2/roles/%d", roleID), "")
-	if err != nil {
-		return Role{}, err
+func (f UserFilter) values() url.Values {
+	values := url.Values{}
+	if f.Email != "" {
+		values.Set("email", f.Email)
 	}
-	role := Role{}
-	err = json.Unmarshal(response, &role)
-	return role, err
+	if f.RoleID != 0 {
+		values.Set("role_id", strconv.FormatInt(f.RoleID, 10))
+	}
+	if f.AccountID != 0 {
+		values.Set("account_id", strconv.FormatInt(f.AccountID, 10))
+	}
+	if f.Active != nil {
+		values.Set("active", strconv.FormatBool(*f.Active))
+	}
+	if f.SuperUser != nil {
+		values.Set("super_user", strconv.FormatBool(*f.SuperUser))
+	}
+	return values
 }
 
-func (bx *Client) GetRoles() ([]Role, error) {
-	response, err := bx.request("GET", "/rest/v2/roles", "")
-	if err != nil {
-		return nil, err
-	}
-	roles := struct {
-		Results []Role `json:"results"`
-	}{}
-	err = json.Unmarshal(response, &roles)
-	return roles.Results, err
+// GetUsers returns every user matching the filter.
+func (bx *Client) GetUsers(ctx context.Context, filter UserFilter) ([]User, error) {
+	return bx.Users().List(ctx, filter.values())
 }
 
-func (bx *Client) CreateRole(role Role) (int64, error) {
-	response, err := bx.request("POST", "/rest/v2/roles", role)
-	if err != nil {
-		return 0, err
-	}
-	createdRole := Role{}
-	err = json.Unmarshal(response, &createdRole)
-	return createdRole.ID, err
+// Roles gives access to the /rest/v2/roles endpoint.
+func (bx *Client) Roles() Resource[Role] {
+	return NewResource[Role](bx, "/rest/v2/roles")
+}
+
+// RoleFilter narrows the roles returned by GetRoles. Empty fields are ignored.
+type RoleFilter struct {
+	Name                 string
+	Archived             *bool
+	SharedAcrossAccounts *bool
+	ParentRoleID         int64
 }
 
-func (bx *Client) UpdateRole(role Role) error {
-	_, err := bx.request("PUT", fmt.Sprintf("/rest/v2/roles/%d", role.ID), role)
-	return err
+func (f RoleFilter) values() url.Values {
+	values := url.Values{}
+	if f.Name != "" {
+		values.Set("name", f.Name)
+	}
+	if f.Archived != nil {
+		values.Set("archived", strconv.FormatBool(*f.Archived))
+	}
+	if f.SharedAcrossAccounts != nil {
+		values.Set("shared_across_accounts", strconv.FormatBool(*f.SharedAcrossAccounts))
+	}
+	if f.ParentRoleID != 0 {
+		values.Set("parent_role_id", strconv.FormatInt(f.ParentRoleID, 10))
+	}
+	return values
 }
 
-func (bx *Client) DeleteRole(roleID int64) error {
-	_, err := bx.request("DELETE", fmt.Sprintf("/rest/v2/roles/%d", roleID), "")
-	return err
+// GetRoles returns every role matching the filter.
+func (bx *Client) GetRoles(ctx context.Context, filter RoleFilter) ([]Role, error) {
+	return bx.Roles().List(ctx, filter.values())
 }
@@ -0,0 +1,133 @@
+package beeswax
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestReloginOnce checks that parallel calls hitting an expired session trigger a single login,
+// and that each of them is replayed with the new session.
+func TestReloginOnce(t *testing.T) {
+	var logins, calls atomic.Int32
+	var session atomic.Value
+	session.Store("")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/v2/authenticate" {
+			value := []string{"first", "second", "third"}[logins.Add(1)-1]
+			session.Store(value)
+			http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: value, Path: "/"})
+			return
+		}
+		calls.Add(1)
+		cookie, err := r.Cookie("sessionid")
+		// The first session expires as soon as it is used
+		if err != nil || cookie.Value == "first" || cookie.Value != session.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 42}`))
+	}))
+	defer server.Close()
+
+	bx, err := NewClient(server.URL, "user@example.com", "secret", Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := bx.Login(context.Background()); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := bx.Roles().Get(context.Background(), 42)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Get: %v", err)
+		}
+	}
+	if logins.Load() != 2 {
+		t.Errorf("%d logins, want the initial one and a single re-authentication", logins.Load())
+	}
+}
+
+func TestReloginFailureIsReported(t *testing.T) {
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/v2/authenticate" {
+			// The account is revoked after the first login
+			if logins.Add(1) > 1 {
+				w.WriteHeader(http.StatusForbidden)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	bx, err := NewClient(server.URL, "user@example.com", "secret", Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	bx.Retry.MaxAttempts = 1
+	_, err = bx.Roles().Get(context.Background(), 42)
+	if err == nil || !strings.Contains(err.Error(), "re-authentication failed") || !IsForbidden(err) {
+		t.Errorf("Get with a revoked account: %v, want a forbidden re-authentication error", err)
+	}
+}
+
+func TestTokenClientSendsBearerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/v2/authenticate" {
+			t.Error("a token client must not log in")
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer myToken" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer myToken")
+		}
+		_, _ = w.Write([]byte(`{"id": 42, "name": "my_role"}`))
+	}))
+	defer server.Close()
+
+	bx, err := NewTokenClient(server.URL, "myToken", Options{})
+	if err != nil {
+		t.Fatalf("NewTokenClient: %v", err)
+	}
+	role, err := bx.Roles().Get(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if role.Name != "my_role" {
+		t.Errorf("role name = %q, want my_role", role.Name)
+	}
+}
+
+func TestClientRequiresHost(t *testing.T) {
+	for name, newClient := range map[string]func() (*Client, error){
+		"password": func() (*Client, error) { return NewClient("", "user@example.com", "secret", Options{}) },
+		"token":    func() (*Client, error) { return NewTokenClient("", "myToken", Options{}) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			bx, err := newClient()
+			if err != nil {
+				t.Fatalf("creating the client: %v", err)
+			}
+			_, err = bx.Roles().Get(context.Background(), 42)
+			if err == nil || !strings.Contains(err.Error(), "host must be known") {
+				t.Errorf("Get without host: %v, want a missing host error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package beeswax
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIError is returned when the Beeswax API answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	// Errors holds the messages parsed from the Beeswax error payload, if any.
+	Errors []ErrorDetail
+	// Body is the raw response, kept for payloads that could not be parsed.
+	Body string
+}
+
+// ErrorDetail is a single message of a Beeswax error payload.
+// Field is the dotted path of the offending attribute, e.g. "email" or "permissions.0.object_type",
+// and is empty for errors not tied to a field.
+type ErrorDetail struct {
+	Field   string
+	Message string
+}
+
+func newAPIError(method, path string, statusCode int, body []byte) *APIError {
+	return &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Errors:     parseErrorDetails(body),
+		Body:       string(body),
+	}
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: response %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if len(e.Errors) == 0 {
+		if e.Body != "" {
+			msg += ". API response: " + e.Body
+		}
+		return msg
+	}
+	details := []string{}
+	for _, d := range e.Errors {
+		if d.Field == "" {
+			details = append(details, d.Message)
+		} else {
+			details = append(details, d.Field+": "+d.Message)
+		}
+	}
+	return msg + ". " + strings.Join(details, "; ")
+}
+
+// IsNotFound reports whether err is an API error with a 404 status.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an API error with a 409 status.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsUnauthorized reports whether err is an API error with a 401 status.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an API error with a 403 status.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// parseErrorDetails extracts messages from the error payloads returned by Beeswax, which come either as
+// {"detail": "..."}, as an "errors" list or as a map of field names to lists of messages.
+func parseErrorDetails(body []byte) []ErrorDetail {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+	details := []ErrorDetail{}
+	collectErrorDetails("", payload, &details)
+	return details
+}
+
+func collectErrorDetails(field string, value interface{}, details *[]ErrorDetail) {
+	switch v := value.(type) {
+	case string:
+		*details = append(*details, ErrorDetail{Field: field, Message: v})
+	case []interface{}:
+		for i, item := range v {
+			// Lists of objects are indexed, lists of messages are not
+			if _, ok := item.(map[string]interface{}); ok && field != "" {
+				collectErrorDetails(fmt.Sprintf("%s.%d", field, i), item, details)
+			} else {
+				collectErrorDetails(field, item, details)
+			}
+		}
+	case map[string]interface{}:
+		// {"field": "email", "message": "..."} style entries
+		if message, ok := v["message"].(string); ok {
+			if name, ok := v["field"].(string); ok {
+				*details = append(*details, ErrorDetail{Field: joinField(field, name), Message: message})
+				return
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch key {
+			case "detail", "message", "error", "errors", "non_field_errors", "success":
+				if key != "success" {
+					collectErrorDetails(field, v[key], details)
+				}
+			default:
+				collectErrorDetails(joinField(field, key), v[key], details)
+			}
+		}
+	}
+}
+
+func joinField(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
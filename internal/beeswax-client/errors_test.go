@@ -0,0 +1,82 @@
+package beeswax
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorDetails(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []ErrorDetail
+	}{
+		{
+			name: "detail",
+			body: `{"detail": "Authentication credentials were not provided."}`,
+			want: []ErrorDetail{{Message: "Authentication credentials were not provided."}},
+		},
+		{
+			name: "errors list",
+			body: `{"success": false, "errors": ["Role is archived", "Account is inactive"]}`,
+			want: []ErrorDetail{{Message: "Role is archived"}, {Message: "Account is inactive"}},
+		},
+		{
+			name: "field map",
+			body: `{"email": ["Enter a valid email address."], "role_id": ["Invalid pk."]}`,
+			want: []ErrorDetail{{Field: "email", Message: "Enter a valid email address."}, {Field: "role_id", Message: "Invalid pk."}},
+		},
+		{
+			name: "nested objects",
+			body: `{"permissions": [{}, {"object_type": ["Unknown object type."]}]}`,
+			want: []ErrorDetail{{Field: "permissions.1.object_type", Message: "Unknown object type."}},
+		},
+		{
+			name: "field and message entries",
+			body: `{"errors": [{"field": "name", "message": "Name already exists"}]}`,
+			want: []ErrorDetail{{Field: "name", Message: "Name already exists"}},
+		},
+		{
+			name: "non field errors",
+			body: `{"non_field_errors": ["Parent role is not a system role"]}`,
+			want: []ErrorDetail{{Message: "Parent role is not a system role"}},
+		},
+		{
+			name: "not JSON",
+			body: `<html>Bad Gateway</html>`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		got := parseErrorDetails([]byte(tt.body))
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseErrorDetails() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	err := newAPIError("POST", "/rest/v2/users", http.StatusBadRequest, []byte(`{"email": ["Enter a valid email address."]}`))
+	if got, want := err.Error(), "POST /rest/v2/users: response 400 Bad Request. email: Enter a valid email address."; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	raw := newAPIError("GET", "/rest/v2/roles/1", http.StatusBadGateway, []byte("upstream down"))
+	if !strings.HasSuffix(raw.Error(), "API response: upstream down") {
+		t.Errorf("Error() = %q, want the raw body", raw.Error())
+	}
+
+	wrapped := fmt.Errorf("could not read role: %w", newAPIError("GET", "/rest/v2/roles/1", http.StatusNotFound, nil))
+	if !IsNotFound(wrapped) || IsConflict(wrapped) || IsUnauthorized(wrapped) || IsForbidden(wrapped) {
+		t.Errorf("status helpers do not match a wrapped 404: %v", wrapped)
+	}
+	if IsNotFound(fmt.Errorf("plain error")) {
+		t.Error("IsNotFound matched an error which is not an APIError")
+	}
+}
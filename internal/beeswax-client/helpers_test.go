@@ -0,0 +1,28 @@
+package beeswax
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client talking to a stub Beeswax API that accepts any login and
+// serves every other path with handler. Retries are disabled.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/v2/authenticate" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	bx, err := NewClient(server.URL, "user@example.com", "secret", Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	bx.Retry.MaxAttempts = 1
+	return bx
+}
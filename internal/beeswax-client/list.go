@@ -0,0 +1,63 @@
+package beeswax
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the number of objects requested per page when Client.PageSize is not set.
+const DefaultPageSize = 100
+
+// listPage is the envelope of every Beeswax list endpoint.
+type listPage[T any] struct {
+	Results []T     `json:"results"`
+	Next    *string `json:"next"`
+}
+
+// listAll returns every object of a list endpoint. Pages are requested with limit/offset and
+// fetched until the API stops returning a next link; the offset given in that link is honoured
+// only when it moves forward, so a server repeating the same page cannot loop forever.
+func listAll[T any](ctx context.Context, bx *Client, path string, values url.Values) ([]T, error) {
+	pageSize := bx.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if values == nil {
+		values = url.Values{}
+	}
+
+	objects := []T{}
+	for offset := 0; ; {
+		values.Set("limit", strconv.Itoa(pageSize))
+		values.Set("offset", strconv.Itoa(offset))
+		response, err := bx.request(ctx, "GET", path+"?"+values.Encode(), "")
+		if err != nil {
+			return nil, err
+		}
+		page := listPage[T]{}
+		if err := json.Unmarshal(response, &page); err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Results...)
+		if page.Next == nil || *page.Next == "" || len(page.Results) == 0 {
+			return objects, nil
+		}
+		offset = nextOffset(*page.Next, offset, offset+len(page.Results))
+	}
+}
+
+// nextOffset reads the offset of a next link, falling back to the given offset when the link
+// has none or does not point past the current offset.
+func nextOffset(next string, current, fallback int) int {
+	u, err := url.Parse(next)
+	if err != nil {
+		return fallback
+	}
+	offset, err := strconv.Atoi(u.Query().Get("offset"))
+	if err != nil || offset <= current {
+		return fallback
+	}
+	return offset
+}
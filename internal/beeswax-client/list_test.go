@@ -0,0 +1,102 @@
+package beeswax
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// rolePages serves total roles by pages, with a next link built by next from the requested offset
+// and the number of roles returned.
+func rolePages(t *testing.T, total int, requests *[]int, next func(offset, count int) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		*requests = append(*requests, offset)
+		if len(*requests) > 20 {
+			t.Errorf("too many page requests: %v", *requests)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		page := map[string]interface{}{"results": []Role{}}
+		roles := []Role{}
+		for id := offset; id < offset+limit && id < total; id++ {
+			roles = append(roles, Role{ID: int64(id + 1)})
+		}
+		page["results"] = roles
+		if offset+len(roles) < total {
+			page["next"] = next(offset, len(roles))
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}
+}
+
+func TestListAllFollowsNextOffset(t *testing.T) {
+	var requests []int
+	bx := newTestClient(t, rolePages(t, 5, &requests, func(offset, count int) string {
+		return fmt.Sprintf("https://example.com/rest/v2/roles?limit=2&offset=%d", offset+count)
+	}))
+	bx.PageSize = 2
+
+	roles, err := bx.GetRoles(context.Background(), RoleFilter{})
+	if err != nil {
+		t.Fatalf("GetRoles: %v", err)
+	}
+	if len(roles) != 5 {
+		t.Errorf("got %d roles, want 5", len(roles))
+	}
+	if fmt.Sprint(requests) != "[0 2 4]" {
+		t.Errorf("requested offsets %v, want [0 2 4]", requests)
+	}
+}
+
+func TestListAllIgnoresNextOffsetNotMovingForward(t *testing.T) {
+	for name, next := range map[string]func(offset, count int) string{
+		"same offset": func(offset, _ int) string { return fmt.Sprintf("/rest/v2/roles?offset=%d", offset) },
+		"first page":  func(_, _ int) string { return "/rest/v2/roles?offset=0" },
+		"no offset":   func(_, _ int) string { return "/rest/v2/roles?page=2" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var requests []int
+			bx := newTestClient(t, rolePages(t, 5, &requests, next))
+			bx.PageSize = 2
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			roles, err := bx.GetRoles(ctx, RoleFilter{})
+			if err != nil {
+				t.Fatalf("GetRoles: %v", err)
+			}
+			if len(roles) != 5 {
+				t.Errorf("got %d roles, want 5", len(roles))
+			}
+			if fmt.Sprint(requests) != "[0 2 4]" {
+				t.Errorf("requested offsets %v, want [0 2 4]", requests)
+			}
+		})
+	}
+}
+
+func TestNextOffset(t *testing.T) {
+	tests := []struct {
+		next    string
+		current int
+		want    int
+	}{
+		{"https://example.com/rest/v2/roles?limit=100&offset=200", 100, 200},
+		{"/rest/v2/roles?offset=100", 100, 150},
+		{"/rest/v2/roles?offset=50", 100, 150},
+		{"/rest/v2/roles?offset=abc", 100, 150},
+		{"/rest/v2/roles", 100, 150},
+		{"://bad", 100, 150},
+	}
+	for _, tt := range tests {
+		if got := nextOffset(tt.next, tt.current, 150); got != tt.want {
+			t.Errorf("nextOffset(%q, %d, 150) = %d, want %d", tt.next, tt.current, got, tt.want)
+		}
+	}
+}
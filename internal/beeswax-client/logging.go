@@ -0,0 +1,103 @@
+package beeswax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redacted replaces credentials in logged headers and bodies.
+const redacted = "***"
+
+// sensitiveKeys are the JSON keys, compared case-insensitively, whose values are never logged.
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"session":       true,
+	"session_id":    true,
+	"api_key":       true,
+}
+
+// sensitiveHeaders are the HTTP headers whose values are never logged.
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// logExchange reports an HTTP attempt: a summary at debug level, headers and bodies at trace level.
+// Logs are only emitted when enabled through TF_LOG or TF_LOG_PROVIDER.
+func logExchange(ctx context.Context, req *http.Request, payload []byte, resp *http.Response, body []byte, latency time.Duration, err error) {
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"path":       req.URL.Path,
+		"latency_ms": latency.Milliseconds(),
+	}
+	if resp != nil {
+		fields["status"] = resp.StatusCode
+		if id := resp.Header.Get("X-Request-Id"); id != "" {
+			fields["request_id"] = id
+		}
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	tflog.Debug(ctx, "Beeswax API request", fields)
+
+	fields["request_headers"] = redactHeaders(req.Header)
+	fields["request_body"] = redactBody(payload)
+	if resp != nil {
+		fields["response_headers"] = redactHeaders(resp.Header)
+		fields["response_body"] = redactBody(body)
+	}
+	tflog.Trace(ctx, "Beeswax API exchange", fields)
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			result[name] = redacted
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// redactBody masks sensitive values of a JSON body. Bodies that are not JSON are logged as is.
+func redactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	masked, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return string(body)
+	}
+	return string(masked)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveKeys[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
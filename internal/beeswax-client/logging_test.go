@@ -0,0 +1,50 @@
+package beeswax
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"email":"user@example.com","password":"secret"}`, `{"email":"user@example.com","password":"***"}`},
+		{`{"Token":"abc","results":[{"id":1,"api_key":"k"}]}`, `{"Token":"***","results":[{"api_key":"***","id":1}]}`},
+		{`{"session":{"id":"s"},"name":"my_role"}`, `{"name":"my_role","session":"***"}`},
+		{`[{"refresh_token":"r"}]`, `[{"refresh_token":"***"}]`},
+		{`not json`, `not json`},
+		{``, ``},
+	}
+	for _, tt := range tests {
+		if got := redactBody([]byte(tt.body)); got != tt.want {
+			t.Errorf("redactBody(%s) = %s, want %s", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{
+		"Content-Type":  []string{"application/json"},
+		"Cookie":        []string{"sessionid=abc"},
+		"Set-Cookie":    []string{"sessionid=abc; Path=/", "csrftoken=def"},
+		"Authorization": []string{"Bearer myToken"},
+		"X-Request-Id":  []string{"42"},
+	}
+	got := redactHeaders(header)
+	for _, name := range []string{"Cookie", "Set-Cookie", "Authorization"} {
+		if got[name] != redacted {
+			t.Errorf("header %s = %q, want it redacted", name, got[name])
+		}
+	}
+	if got["Content-Type"] != "application/json" || got["X-Request-Id"] != "42" {
+		t.Errorf("other headers were not kept: %v", got)
+	}
+	for _, value := range got {
+		if strings.Contains(value, "abc") || strings.Contains(value, "myToken") {
+			t.Errorf("a credential leaked in %v", got)
+		}
+	}
+}
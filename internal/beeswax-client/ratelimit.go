@@ -0,0 +1,43 @@
+package beeswax
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// SetLimits throttles the client to requestsPerSecond HTTP requests (token bucket) and to
+// maxConcurrentRequests requests in flight at once, shared by every caller of the client.
+// Zero or negative values disable the corresponding limit. It must be called before the client is used.
+func (bx *Client) SetLimits(requestsPerSecond float64, maxConcurrentRequests int) {
+	bx.limiter = nil
+	if requestsPerSecond > 0 {
+		burst := int(requestsPerSecond)
+		if burst < 1 {
+			burst = 1
+		}
+		bx.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+	bx.slots = nil
+	if maxConcurrentRequests > 0 {
+		bx.slots = make(chan struct{}, maxConcurrentRequests)
+	}
+}
+
+// acquire waits for a rate limit token and a concurrency slot. The returned function releases the slot.
+func (bx *Client) acquire(ctx context.Context) (func(), error) {
+	if bx.limiter != nil {
+		if err := bx.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if bx.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case bx.slots <- struct{}{}:
+		return func() { <-bx.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
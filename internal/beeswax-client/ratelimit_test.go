@@ -0,0 +1,97 @@
+package beeswax
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	bx := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	bx.SetLimits(0, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := bx.Roles().Get(context.Background(), 1); err != nil {
+				t.Errorf("Get: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got < 1 || got > 2 {
+		t.Errorf("%d requests were in flight at once, want at most 2", got)
+	}
+}
+
+func TestAcquireReturnsWhenCancelled(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestsPerSecond float64
+		maxConcurrent     int
+	}{
+		{"waiting for a slot", 0, 1},
+		{"waiting for a token", 0.1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bx := newTestClient(t, nil)
+			bx.SetLimits(tt.requestsPerSecond, tt.maxConcurrent)
+			release, err := bx.acquire(context.Background())
+			if err != nil {
+				t.Fatalf("first acquire: %v", err)
+			}
+			defer release()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+			done := make(chan error, 1)
+			go func() {
+				_, err := bx.acquire(ctx)
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("acquire error = %v, want context.Canceled", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("acquire did not return once the context was cancelled")
+			}
+		})
+	}
+}
+
+func TestSetLimitsZeroDisablesLimits(t *testing.T) {
+	bx := newTestClient(t, nil)
+	bx.SetLimits(1, 1)
+	bx.SetLimits(0, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// Neither the token bucket nor the slots would let more than one call through within the timeout
+	for i := 0; i < 50; i++ {
+		if _, err := bx.acquire(ctx); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package beeswax
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Resource gives typed CRUD access to a Beeswax object endpoint such as /rest/v2/users.
+// Supporting a new object type only requires its struct and a Resource pointing to its endpoint.
+type Resource[T any] struct {
+	client *Client
+	path   string
+}
+
+// NewResource returns the Resource of type T served under path, e.g. "/rest/v2/advertisers".
+func NewResource[T any](client *Client, path string) Resource[T] {
+	return Resource[T]{client: client, path: path}
+}
+
+// Get returns the object with the given ID.
+func (r Resource[T]) Get(ctx context.Context, id int64) (T, error) {
+	return r.call(ctx, "GET", r.objectPath(id), "")
+}
+
+// List returns every object matching the query, following the API pagination.
+func (r Resource[T]) List(ctx context.Context, query url.Values) ([]T, error) {
+	return listAll[T](ctx, r.client, r.path, query)
+}
+
+// Create creates the object and returns it as stored by the API.
+func (r Resource[T]) Create(ctx context.Context, object T) (T, error) {
+	return r.call(ctx, "POST", r.path, object)
+}
+
+// Update replaces the object with the given ID and returns it as stored by the API.
+func (r Resource[T]) Update(ctx context.Context, id int64, object T) (T, error) {
+	return r.call(ctx, "PUT", r.objectPath(id), object)
+}
+
+// Patch only modifies the given fields, keyed by their JSON name, and returns the object as stored by the API.
+func (r Resource[T]) Patch(ctx context.Context, id int64, fields map[string]interface{}) (T, error) {
+	return r.call(ctx, "PATCH", r.objectPath(id), fields)
+}
+
+// Delete deletes the object with the given ID.
+func (r Resource[T]) Delete(ctx context.Context, id int64) error {
+	_, err := r.client.request(ctx, "DELETE", r.objectPath(id), "")
+	return err
+}
+
+func (r Resource[T]) objectPath(id int64) string {
+	return fmt.Sprintf("%s/%d", r.path, id)
+}
+
+// call performs the request and decodes the returned object, if any.
+func (r Resource[T]) call(ctx context.Context, method, path string, data interface{}) (T, error) {
+	var object T
+	response, err := r.client.request(ctx, method, path, data)
+	if err != nil {
+		return object, err
+	}
+	if len(response) == 0 {
+		return object, nil
+	}
+	if err := json.Unmarshal(response, &object); err != nil {
+		return object, fmt.Errorf("can't decode %s %s response: %w", method, path, err)
+	}
+	return object, nil
+}
@@ -0,0 +1,117 @@
+package beeswax
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	transportErr := errors.New("connection reset")
+	tests := []struct {
+		method string
+		status int
+		err    error
+		want   bool
+	}{
+		{http.MethodGet, http.StatusTooManyRequests, nil, true},
+		{http.MethodPost, http.StatusTooManyRequests, nil, true},
+		{http.MethodPost, http.StatusServiceUnavailable, nil, true},
+		{http.MethodGet, http.StatusBadGateway, nil, true},
+		{http.MethodPost, http.StatusBadGateway, nil, false},
+		{http.MethodPatch, http.StatusGatewayTimeout, nil, false},
+		{http.MethodDelete, http.StatusGatewayTimeout, nil, true},
+		{http.MethodGet, http.StatusInternalServerError, nil, false},
+		{http.MethodGet, http.StatusNotFound, nil, false},
+		{http.MethodGet, http.StatusOK, nil, false},
+		{http.MethodGet, 0, transportErr, true},
+		{http.MethodPost, 0, transportErr, false},
+	}
+	for _, tt := range tests {
+		var resp *http.Response
+		if tt.status != 0 {
+			resp = &http.Response{StatusCode: tt.status}
+		}
+		if got := policy.shouldRetry(tt.method, resp, tt.err); got != tt.want {
+			t.Errorf("shouldRetry(%s, %d, %v) = %v, want %v", tt.method, tt.status, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDelay(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, want := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		30: time.Second,
+	} {
+		if got := policy.delay(attempt, nil); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	policy.Jitter = true
+	for i := 0; i < 100; i++ {
+		if got := policy.delay(3, nil); got < 200*time.Millisecond || got > 400*time.Millisecond {
+			t.Fatalf("delay(3) with jitter = %v, want between 200ms and 400ms", got)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if got := policy.delay(1, resp); got != time.Second {
+		t.Errorf("delay with Retry-After above the maximum = %v, want 1s", got)
+	}
+	policy.MaxBackoff = 10 * time.Second
+	if got := policy.delay(1, resp); got != 7*time.Second {
+		t.Errorf("delay with Retry-After = %v, want 7s", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("120"); !ok || d != 2*time.Minute {
+		t.Errorf("parseRetryAfter(120) = %v, %v", d, ok)
+	}
+	if d, ok := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || d < 59*time.Minute || d > time.Hour {
+		t.Errorf("parseRetryAfter(date in one hour) = %v, %v", d, ok)
+	}
+	if d, ok := parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)); !ok || d != 0 {
+		t.Errorf("parseRetryAfter(past date) = %v, %v", d, ok)
+	}
+	for _, value := range []string{"", "-1", "soon"} {
+		if _, ok := parseRetryAfter(value); ok {
+			t.Errorf("parseRetryAfter(%q) should be rejected", value)
+		}
+	}
+}
+
+func TestSendRetriesThrottledCalls(t *testing.T) {
+	var calls atomic.Int32
+	bx := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 42}`))
+	})
+	bx.Retry = RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+
+	if _, err := bx.Roles().Get(context.Background(), 42); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("%d calls, want 3", calls.Load())
+	}
+
+	calls.Store(0)
+	bx.Retry.MaxAttempts = 2
+	_, err := bx.Roles().Get(context.Background(), 42)
+	if err == nil || calls.Load() != 2 {
+		t.Errorf("Get with 2 attempts: %v after %d calls, want an error after 2 calls", err, calls.Load())
+	}
+}
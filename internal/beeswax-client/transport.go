@@ -0,0 +1,75 @@
+package beeswax
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Options configures how a Client reaches the Beeswax API. The zero value uses the system
+// certificate authorities, the proxy from the environment and DefaultRequestTimeout.
+type Options struct {
+	// CACertFile is a PEM bundle of certificate authorities trusted in addition to the system ones.
+	CACertFile string
+	// ProxyURL routes every request through this proxy. When empty HTTPS_PROXY, HTTP_PROXY and NO_PROXY apply.
+	ProxyURL string
+	// InsecureSkipVerify disables the verification of the server certificate. Only meant for testing.
+	InsecureSkipVerify bool
+	// ClientCertFile and ClientKeyFile are the PEM certificate and key presented for mutual TLS, set together.
+	ClientCertFile string
+	ClientKeyFile  string
+	// RequestTimeout overrides DefaultRequestTimeout when positive.
+	RequestTimeout time.Duration
+}
+
+// transport builds the HTTP transport described by the options.
+func (o Options) transport() (*http.Transport, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+
+	if o.ProxyURL != "" {
+		proxy, err := url.Parse(o.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+	if o.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(o.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't read CA certificate file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificate found in %s", o.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if o.ClientCertFile != "" || o.ClientKeyFile != "" {
+		if o.ClientCertFile == "" || o.ClientKeyFile == "" {
+			return nil, fmt.Errorf("the client certificate and key files must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
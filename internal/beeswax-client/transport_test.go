@@ -0,0 +1,95 @@
+package beeswax
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a self-signed PEM certificate and its key to a temporary directory.
+func writeCertificate(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating the key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "beeswax test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating the certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("encoding the key: %v", err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certFile, keyFile
+}
+
+func writeFile(t *testing.T, name string, content []byte) {
+	t.Helper()
+	if err := os.WriteFile(name, content, 0o600); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	writeFile(t, notPEM, []byte("not a certificate"))
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+
+	tests := []struct {
+		name    string
+		options Options
+		wantErr string
+	}{
+		{"defaults", Options{}, ""},
+		{"CA and client certificate", Options{CACertFile: certFile, ClientCertFile: certFile, ClientKeyFile: keyFile, ProxyURL: "http://proxy.example.com:3128"}, ""},
+		{"CA file not PEM", Options{CACertFile: notPEM}, "no PEM certificate found"},
+		{"unreadable CA file", Options{CACertFile: missing}, "can't read CA certificate file"},
+		{"unreadable client key file", Options{ClientCertFile: certFile, ClientKeyFile: missing}, "can't load client certificate"},
+		{"client certificate without key", Options{ClientCertFile: certFile}, "must be set together"},
+		{"client key without certificate", Options{ClientKeyFile: keyFile}, "must be set together"},
+		{"bad proxy URL", Options{ProxyURL: "://proxy.example.com"}, "invalid proxy URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := tt.options.transport()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("transport: %v", err)
+				}
+				if transport.TLSClientConfig.RootCAs == nil && tt.options.CACertFile != "" {
+					t.Error("the CA file was not added to the trusted certificates")
+				}
+				if len(transport.TLSClientConfig.Certificates) == 0 && tt.options.ClientCertFile != "" {
+					t.Error("the client certificate is not presented")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("transport error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultProfile is the credentials file profile used when none is configured.
+const defaultProfile = "default"
+
+// maxProcessErrorOutput is the number of bytes of the credential_process error output kept in debug logs.
+const maxProcessErrorOutput = 1024
+
+// credentialsProfile holds the values of a profile of the Beeswax credentials file.
+// It is also the JSON document printed by credential_process.
+type credentialsProfile struct {
+	Host     string `json:"host"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	APIToken string `json:"api_token"`
+}
+
+// complete reports whether the host and the credentials of one authentication mode are all set.
+func (c credentialsProfile) complete() bool {
+	return c.Host != "" && (c.APIToken != "" || (c.Email != "" && c.Password != ""))
+}
+
+// credentialsFilePath returns the location of the Beeswax credentials file, ~/.beeswax/credentials.
+func credentialsFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".beeswax", "credentials"), nil
+}
+
+// loadCredentialsProfile reads a profile of the Beeswax credentials file, an INI file such as:
+//
+//	[default]
+//	host     = https://myorg.api.beeswax.com
+//	email    = myemail@myorg.com
+//	password = myPasswd
+//
+// An api_token key may replace email and password.
+// found is false when the file or the profile does not exist.
+func loadCredentialsProfile(name string) (profile credentialsProfile, found bool, err error) {
+	file, err := credentialsFilePath()
+	if err != nil {
+		return profile, false, err
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profile, false, nil
+	}
+	if err != nil {
+		return profile, false, err
+	}
+	defer f.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			found = found || section == name
+			continue
+		}
+		// Other profiles are not validated, they may be meant for other tools
+		if section != name {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return profile, false, fmt.Errorf("%s:%d: expected 'key = value'", file, lineNumber)
+		}
+		switch strings.TrimSpace(key) {
+		case "host":
+			profile.Host = strings.TrimSpace(value)
+		case "email":
+			profile.Email = strings.TrimSpace(value)
+		case "password":
+			profile.Password = strings.TrimSpace(value)
+		case "api_token":
+			profile.APIToken = strings.TrimSpace(value)
+		default:
+			return profile, false, fmt.Errorf("%s:%d: unknown key '%s'", file, lineNumber, strings.TrimSpace(key))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return profile, false, err
+	}
+	return profile, found, nil
+}
+
+// runCredentialProcess executes command through the system shell and decodes the credentials it prints
+// on stdout as JSON, e.g. {"host": "https://myorg.api.beeswax.com", "email": "myemail@myorg.com", "password": "myPasswd"}.
+// Fields may be omitted to take them from other sources, and an "api_token" field may replace email and password.
+func runCredentialProcess(ctx context.Context, command string) (credentialsProfile, error) {
+	var credentials credentialsProfile
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// stderr may echo secrets, it is only logged at debug level, truncated
+		output := strings.TrimSpace(stderr.String())
+		if len(output) > maxProcessErrorOutput {
+			output = output[:maxProcessErrorOutput] + "..."
+		}
+		tflog.Debug(ctx, "Beeswax credential_process failed", map[string]interface{}{"stderr": output})
+		return credentials, fmt.Errorf("%w, run Terraform with TF_LOG=DEBUG to see its error output", err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		// stdout holds secrets, never include it in the error
+		return credentials, errors.New("the output is not a JSON object with host, email, password or api_token")
+	}
+	return credentials, nil
+}
+
+// resolveCredentials merges credential sources given by order of precedence. The host and email come from
+// the first source defining them. The first source defining a password or an API token selects the
+// authentication mode: a source defining both is rejected and the other mode of later sources is ignored.
+func resolveCredentials(sources ...credentialsProfile) (credentialsProfile, error) {
+	var resolved credentialsProfile
+	authenticated := false
+	for _, source := range sources {
+		resolved.Host = cmp.Or(resolved.Host, source.Host)
+		resolved.Email = cmp.Or(resolved.Email, source.Email)
+		if authenticated {
+			continue
+		}
+		if source.Password != "" && source.APIToken != "" {
+			return resolved, errors.New("a password and an API token are set together, only one authentication mode can be used")
+		}
+		resolved.Password, resolved.APIToken = source.Password, source.APIToken
+		authenticated = source.Password != "" || source.APIToken != ""
+	}
+	return resolved, nil
+}
@@ -0,0 +1,301 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
+)
+
+// writeCredentialsFile points the home directory to a temporary one holding content as credentials file.
+func writeCredentialsFile(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.MkdirAll(filepath.Join(home, ".beeswax"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".beeswax", "credentials"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	writeCredentialsFile(t, `# Beeswax credentials
+[default]
+host = https://myorg.api.beeswax.com
+email    = myemail@myorg.com
+password = my=Passwd
+
+; another organization
+[other]
+some junk line
+unknown_key = value
+
+[ci]
+host      = https://ci.api.beeswax.com
+api_token = myToken
+`)
+
+	tests := []struct {
+		name  string
+		want  credentialsProfile
+		found bool
+	}{
+		{"default", credentialsProfile{Host: "https://myorg.api.beeswax.com", Email: "myemail@myorg.com", Password: "my=Passwd"}, true},
+		{"ci", credentialsProfile{Host: "https://ci.api.beeswax.com", APIToken: "myToken"}, true},
+		{"missing", credentialsProfile{}, false},
+	}
+	for _, tt := range tests {
+		got, found, err := loadCredentialsProfile(tt.name)
+		if err != nil {
+			t.Errorf("loadCredentialsProfile(%q): %v", tt.name, err)
+			continue
+		}
+		if got != tt.want || found != tt.found {
+			t.Errorf("loadCredentialsProfile(%q) = %+v, %v, want %+v, %v", tt.name, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestLoadCredentialsProfileInvalidSelectedProfile(t *testing.T) {
+	writeCredentialsFile(t, "[default]\nsome junk line\n")
+	if _, _, err := loadCredentialsProfile("default"); err == nil {
+		t.Error("expected an error for a malformed line of the selected profile")
+	}
+
+	writeCredentialsFile(t, "[default]\nusername = me\n")
+	if _, _, err := loadCredentialsProfile("default"); err == nil {
+		t.Error("expected an error for an unknown key of the selected profile")
+	}
+}
+
+func TestLoadCredentialsProfileWithoutFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	_, found, err := loadCredentialsProfile(defaultProfile)
+	if err != nil || found {
+		t.Errorf("loadCredentialsProfile without file = %v, %v, want not found", found, err)
+	}
+}
+
+// TestConfigureSkipsCredentialsFile checks that a broken credentials file does not matter
+// when no profile is asked for and the environment provides every value.
+func TestConfigureSkipsCredentialsFile(t *testing.T) {
+	writeCredentialsFile(t, "[default]\nsome junk line\n")
+	t.Setenv("BEESWAX_PROFILE", "")
+	t.Setenv("BEESWAX_HOST", "https://myorg.api.beeswax.com")
+	t.Setenv("BEESWAX_EMAIL", "myemail@myorg.com")
+	t.Setenv("BEESWAX_PASSWORD", "myPasswd")
+	t.Setenv("BEESWAX_API_TOKEN", "")
+
+	resp := configureProvider(t, beeswaxProviderModel{}, false)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", resp.Diagnostics)
+	}
+	if resp.ResourceData == nil {
+		t.Error("Configure did not create the client")
+	}
+
+	// Without the password, the file is needed and its error reported
+	t.Setenv("BEESWAX_PASSWORD", "")
+	if resp := configureProvider(t, beeswaxProviderModel{}, false); !resp.Diagnostics.HasError() {
+		t.Error("expected the malformed credentials file to be reported")
+	}
+}
+
+// configureProvider runs the provider Configure with the given configuration, from a Terraform
+// client allowing deferred changes or not.
+func configureProvider(t *testing.T, config beeswaxProviderModel, deferralAllowed bool) *provider.ConfigureResponse {
+	t.Helper()
+	ctx := context.Background()
+	p := &beeswaxProvider{version: "test"}
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, config); diags.HasError() {
+		t.Fatalf("building the configuration: %v", diags)
+	}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config:             tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw},
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: deferralAllowed},
+	}, resp)
+	return resp
+}
+
+// TestConfigureUnknownCredentials checks that a credential unknown until apply is never taken from
+// another source: the provider defers when Terraform allows it, and reports the value otherwise.
+func TestConfigureUnknownCredentials(t *testing.T) {
+	writeCredentialsFile(t, "[default]\npassword = fromFile\n")
+	t.Setenv("BEESWAX_PROFILE", "")
+	t.Setenv("BEESWAX_HOST", "https://myorg.api.beeswax.com")
+	t.Setenv("BEESWAX_EMAIL", "myemail@myorg.com")
+	t.Setenv("BEESWAX_PASSWORD", "fromEnv")
+	t.Setenv("BEESWAX_API_TOKEN", "")
+	config := beeswaxProviderModel{Password: types.StringUnknown()}
+
+	resp := configureProvider(t, config, true)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", resp.Diagnostics)
+	}
+	if resp.Deferred == nil || resp.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+		t.Errorf("deferred = %+v, want the provider configuration unknown reason", resp.Deferred)
+	}
+	if resp.ResourceData != nil {
+		t.Error("a deferred Configure must not create a client")
+	}
+
+	resp = configureProvider(t, config, false)
+	if resp.ResourceData != nil {
+		t.Error("Configure created a client from a fallback password")
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Fatalf("diagnostics = %v, want a single unknown password error", resp.Diagnostics)
+	}
+	withPath, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("password")) || withPath.Summary() != "Unknown Beeswax API password" {
+		t.Errorf("diagnostics = %v, want a single unknown password error", resp.Diagnostics)
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []credentialsProfile
+		want    credentialsProfile
+		wantErr bool
+	}{
+		{
+			name:    "values spread across sources",
+			sources: []credentialsProfile{{Email: "config@myorg.com"}, {}, {Host: "https://env", Password: "envPasswd"}, {Host: "https://file", Email: "file@myorg.com", Password: "filePasswd"}},
+			want:    credentialsProfile{Host: "https://env", Email: "config@myorg.com", Password: "envPasswd"},
+		},
+		{
+			name:    "first credential selects token mode",
+			sources: []credentialsProfile{{Host: "https://config"}, {APIToken: "processToken"}, {Email: "env@myorg.com", Password: "envPasswd"}},
+			want:    credentialsProfile{Host: "https://config", Email: "env@myorg.com", APIToken: "processToken"},
+		},
+		{
+			name:    "first credential selects password mode",
+			sources: []credentialsProfile{{Password: "configPasswd"}, {APIToken: "envToken"}},
+			want:    credentialsProfile{Password: "configPasswd"},
+		},
+		{
+			name:    "password and token in the same source",
+			sources: []credentialsProfile{{}, {Password: "envPasswd", APIToken: "envToken"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := resolveCredentials(tt.sources...)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("%s: resolveCredentials() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCredentialsComplete(t *testing.T) {
+	tests := []struct {
+		credentials credentialsProfile
+		want        bool
+	}{
+		{credentialsProfile{Host: "h", Email: "e", Password: "p"}, true},
+		{credentialsProfile{Host: "h", APIToken: "t"}, true},
+		{credentialsProfile{Host: "h", Password: "p"}, false},
+		{credentialsProfile{Email: "e", Password: "p"}, false},
+		{credentialsProfile{APIToken: "t"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.credentials.complete(); got != tt.want {
+			t.Errorf("%+v.complete() = %v, want %v", tt.credentials, got, tt.want)
+		}
+	}
+}
+
+func TestRunCredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are written for sh")
+	}
+	ctx := context.Background()
+
+	got, err := runCredentialProcess(ctx, `echo '{"host": "https://myorg.api.beeswax.com", "api_token": "processToken"}'`)
+	if err != nil {
+		t.Fatalf("runCredentialProcess: %v", err)
+	}
+	if want := (credentialsProfile{Host: "https://myorg.api.beeswax.com", APIToken: "processToken"}); got != want {
+		t.Errorf("runCredentialProcess = %+v, want %+v", got, want)
+	}
+
+	_, err = runCredentialProcess(ctx, "echo 'token processToken expired' >&2; exit 3")
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("runCredentialProcess error = %v, want the exit status", err)
+	} else if strings.Contains(err.Error(), "processToken") {
+		t.Errorf("runCredentialProcess error %q must not include the error output", err)
+	}
+
+	_, err = runCredentialProcess(ctx, "echo password=processPasswd")
+	if err == nil {
+		t.Error("expected an output that is not JSON to be reported")
+	} else if strings.Contains(err.Error(), "processPasswd") {
+		t.Errorf("runCredentialProcess error %q must not include the output", err)
+	}
+}
+
+// TestConfigureCredentialProcessPrecedence checks that the credential process takes precedence over
+// the environment variables, and that the configuration takes precedence over the credential process.
+func TestConfigureCredentialProcessPrecedence(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is written for sh")
+	}
+	var login map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&login); err != nil {
+			t.Errorf("decoding the login: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	writeCredentialsFile(t, "")
+	t.Setenv("BEESWAX_PROFILE", "")
+	t.Setenv("BEESWAX_HOST", server.URL)
+	t.Setenv("BEESWAX_EMAIL", "env@myorg.com")
+	t.Setenv("BEESWAX_PASSWORD", "envPasswd")
+	t.Setenv("BEESWAX_API_TOKEN", "")
+
+	resp := configureProvider(t, beeswaxProviderModel{
+		Email:             types.StringValue("config@myorg.com"),
+		CredentialProcess: types.StringValue(`echo '{"email": "process@myorg.com", "password": "processPasswd"}'`),
+	}, false)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", resp.Diagnostics)
+	}
+	client, ok := resp.ResourceData.(*beeswax.Client)
+	if !ok {
+		t.Fatalf("Configure did not create the client, got %T", resp.ResourceData)
+	}
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if login["email"] != "config@myorg.com" || login["password"] != "processPasswd" {
+		t.Errorf("logged in as %s with password %s, want config@myorg.com with processPasswd", login["email"], login["password"])
+	}
+}
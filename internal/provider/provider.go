@@ -4,12 +4,18 @@ import (
 	"context"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
 )
@@ -41,6 +47,27 @@ type beeswaxProviderModel struct {
 	Host     types.String `tfsdk:"host"`
 	Email    types.String `tfsdk:"email"`
 	Password types.String `tfsdk:"password"`
+	APIToken types.String `tfsdk:"api_token"`
+	Profile  types.String `tfsdk:"profile"`
+
+	CredentialProcess types.String `tfsdk:"credential_process"`
+
+	RetryMaxAttempts types.Int64  `tfsdk:"retry_max_attempts"`
+	RetryBaseBackoff types.String `tfsdk:"retry_base_backoff"`
+	RetryMaxBackoff  types.String `tfsdk:"retry_max_backoff"`
+	RetryJitter      types.Bool   `tfsdk:"retry_jitter"`
+
+	PageSize types.Int64 `tfsdk:"page_size"`
+
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ClientCertFile     types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile      types.String `tfsdk:"client_key_file"`
+	RequestTimeout     types.String `tfsdk:"request_timeout"`
 }
 
 // Metadata returns the provider type name.
@@ -58,7 +85,7 @@ func (p *beeswaxProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				Optional:    true,
 			},
 			"email": schema.StringAttribute{
-				Description: "Email to login to Beeswax API. May also be provided via BEESWAX_USERNAME environment variable.",
+				Description: "Email to login to Beeswax API. May also be provided via BEESWAX_EMAIL environment variable.",
 				Optional:    true,
 			},
 			"password": schema.StringAttribute{
@@ -66,6 +93,78 @@ func (p *beeswaxProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"api_token": schema.StringAttribute{
+				Description: "API token, or pre-issued session token, sent as a bearer token with every request instead of logging in with email and password. Conflicts with password. May also be provided via BEESWAX_API_TOKEN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+				Validators:  []validator.String{stringvalidator.ConflictsWith(path.MatchRoot("password"))},
+			},
+			"profile": schema.StringAttribute{
+				Description: "Profile of the ~/.beeswax/credentials file providing the host, email, password or api_token not set in the configuration or environment. May also be provided via BEESWAX_PROFILE environment variable. Defaults to \"default\".",
+				Optional:    true,
+			},
+			"credential_process": schema.StringAttribute{
+				Description: "Command run through the system shell, printing on stdout a JSON object with the host, email, password or api_token not set in the configuration, e.g. to read them from a secret store. Takes precedence over environment variables and the credentials file.",
+				Optional:    true,
+			},
+			"retry_max_attempts": schema.Int64Attribute{
+				Description: "Total number of attempts for a Beeswax API call, including the first one. Set to 1 to disable retries. Defaults to 4.",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"retry_base_backoff": schema.StringAttribute{
+				Description: "Wait before the first retry, doubled on every following attempt, e.g. \"500ms\". Must be positive, defaults to 500ms.",
+				Optional:    true,
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				Description: "Maximum wait between two attempts, e.g. \"30s\". A Retry-After header sent by Beeswax is honoured up to this maximum. Must be positive, defaults to 30s.",
+				Optional:    true,
+			},
+			"retry_jitter": schema.BoolAttribute{
+				Description: "Randomize the wait between attempts to spread retries of parallel operations. Defaults to true.",
+				Optional:    true,
+			},
+			"page_size": schema.Int64Attribute{
+				Description: "Number of objects requested per page when listing Beeswax objects. Defaults to 100.",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "Maximum number of requests per second sent to the Beeswax API, shared by all parallel operations. Unlimited by default.",
+				Optional:    true,
+				Validators:  []validator.Float64{float64validator.AtLeast(0)},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Maximum number of requests to the Beeswax API in flight at once. Unlimited by default.",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM bundle of certificate authorities trusted in addition to the system ones, e.g. an internal CA re-signing traffic on a proxy.",
+				Optional:    true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "URL of the proxy used to reach the Beeswax API. Defaults to the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.",
+				Optional:    true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip the verification of the Beeswax API TLS certificate. Only meant for testing. Defaults to false.",
+				Optional:    true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM client certificate presented for mutual TLS. Requires client_key_file.",
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.AlsoRequires(path.MatchRoot("client_key_file"))},
+			},
+			"client_key_file": schema.StringAttribute{
+				Description: "Path to the PEM private key of client_cert_file.",
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.AlsoRequires(path.MatchRoot("client_cert_file"))},
+			},
+			"request_timeout": schema.StringAttribute{
+				Description: "Maximum duration of a single HTTP attempt to the Beeswax API, e.g. \"2m\". Must be positive, defaults to 60s.",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -80,86 +179,223 @@ func (p *beeswaxProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	// Check of unknown configuration values
-	addUnknownDiagnostic := func(attr string) {
-		resp.Diagnostics.AddAttributeError(
-			path.Root(attr),
-			"Unknown Beeswax API "+attr,
-			"The provider cannot create the Beeswax API client as there is an unknown configuration value for the Beeswax API '"+attr+"'. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the BEESWAX_"+strings.ToUpper(attr)+" environment variable.")
-	}
-	if config.Host.IsUnknown() {
-		addUnknownDiagnostic("host")
-	}
-	if config.Email.IsUnknown() {
-		addUnknownDiagnostic("email")
+	// Credentials unknown until apply, e.g. a password created in the same run, never fall back to the
+	// environment variables or the credentials file. Terraform defers the resources of the provider when
+	// it supports it, otherwise the value must be known when planning.
+	unknown := false
+	for _, credential := range []struct {
+		attr    string
+		env     string
+		unknown bool
+	}{
+		{"host", "BEESWAX_HOST", config.Host.IsUnknown()},
+		{"email", "BEESWAX_EMAIL", config.Email.IsUnknown()},
+		{"password", "BEESWAX_PASSWORD", config.Password.IsUnknown()},
+		{"api_token", "BEESWAX_API_TOKEN", config.APIToken.IsUnknown()},
+		{"profile", "BEESWAX_PROFILE", config.Profile.IsUnknown()},
+		{"credential_process", "", config.CredentialProcess.IsUnknown()},
+	} {
+		if !credential.unknown {
+			continue
+		}
+		unknown = true
+		if req.ClientCapabilities.DeferralAllowed {
+			continue
+		}
+		detail := "The provider cannot create the Beeswax API client as there is an unknown configuration value for the Beeswax API " + credential.attr + ". " +
+			"Either apply the source of the value first or set the value statically in the configuration"
+		if credential.env != "" {
+			detail += ", or remove it and use the " + credential.env + " environment variable"
+		}
+		resp.Diagnostics.AddAttributeError(path.Root(credential.attr), "Unknown Beeswax API "+credential.attr, detail+".")
 	}
-	if config.Password.IsUnknown() {
-		addUnknownDiagnostic("password")
+	if unknown && req.ClientCapabilities.DeferralAllowed {
+		resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+		return
 	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Check of empty configuration values
-	// Use environment variables as fallbacks
-	host := os.Getenv("BEESWAX_HOST")
-	email := os.Getenv("BEESWAX_EMAIL")
-	password := os.Getenv("BEESWAX_PASSWORD")
-	if !config.Host.IsNull() {
-		host = config.Host.ValueString()
+	// Run the external credential process
+	var processed credentialsProfile
+	if command := config.CredentialProcess.ValueString(); command != "" {
+		var err error
+		processed, err = runCredentialProcess(ctx, command)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_process"),
+				"Beeswax Credential Process Failed",
+				"The credential_process command did not provide Beeswax credentials: "+err.Error())
+			return
+		}
 	}
-	if !config.Email.IsNull() {
-		email = config.Email.ValueString()
+
+	// Values are taken by order of precedence from the configuration, the credential process,
+	// the environment variables, then the credentials file profile.
+	sources := []credentialsProfile{
+		{
+			Host:     config.Host.ValueString(),
+			Email:    config.Email.ValueString(),
+			Password: config.Password.ValueString(),
+			APIToken: config.APIToken.ValueString(),
+		},
+		processed,
+		{
+			Host:     os.Getenv("BEESWAX_HOST"),
+			Email:    os.Getenv("BEESWAX_EMAIL"),
+			Password: os.Getenv("BEESWAX_PASSWORD"),
+			APIToken: os.Getenv("BEESWAX_API_TOKEN"),
+		},
 	}
-	if !config.Password.IsNull() {
-		password = config.Password.ValueString()
+
+	// Read the credentials file profile
+	profileName := os.Getenv("BEESWAX_PROFILE")
+	if !config.Profile.IsNull() {
+		profileName = config.Profile.ValueString()
+	}
+	// The file is only read when a profile is asked for, or when the other sources miss a value
+	var profile credentialsProfile
+	resolved, err := resolveCredentials(sources...)
+	if err == nil && (profileName != "" || !resolved.complete()) {
+		name := profileName
+		if name == "" {
+			name = defaultProfile
+		}
+		var found bool
+		profile, found, err = loadCredentialsProfile(name)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("profile"),
+				"Unable to Read Beeswax Credentials File",
+				"The Beeswax credentials file could not be read: "+err.Error())
+			return
+		}
+		// Only a profile explicitly asked for must exist
+		if !found && profileName != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("profile"),
+				"Unknown Beeswax Profile",
+				"The profile '"+profileName+"' is not defined in the Beeswax credentials file ~/.beeswax/credentials.")
+			return
+		}
+	}
+
+	// Check of empty configuration values
+	credentials, err := resolveCredentials(append(sources, profile)...)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Conflicting Beeswax API Credentials",
+			"The provider cannot choose how to authenticate to the Beeswax API: "+err.Error()+". "+
+				"Remove either the password or the api_token from the configuration, the credential_process output, "+
+				"the environment variables or the profile of the ~/.beeswax/credentials file.")
+		return
 	}
 	addMissingDiagnostic := func(attr string) {
 		resp.Diagnostics.AddAttributeError(
 			path.Root(attr),
 			"Missing Beeswax API "+attr,
 			"The provider cannot create the Beeswax API client as there is a missing or empty value for the Beeswax API "+attr+". "+
-				"Set the '"+attr+"' value in the configuration or use the BEESWAX_"+strings.ToUpper(attr)+" environment variable. "+
+				"Set the '"+attr+"' value in the configuration, print it from the credential_process command, "+
+				"use the BEESWAX_"+strings.ToUpper(attr)+" environment variable or add it to the profile of the ~/.beeswax/credentials file. "+
 				"If either is already set, ensure the value is not empty.")
 	}
-	if host == "" {
+	if credentials.Host == "" {
 		addMissingDiagnostic("host")
 	}
-	if email == "" {
-		addMissingDiagnostic("email")
+	// Email and password are not needed with an API token
+	if credentials.APIToken == "" {
+		if credentials.Email == "" {
+			addMissingDiagnostic("email")
+		}
+		if credentials.Password == "" {
+			addMissingDiagnostic("password")
+		}
 	}
-	if password == "" {
-		addMissingDiagnostic("password")
+	if resp.Diagnostics.HasError() {
+		return
 	}
+
+	retry := retryPolicy(config, &resp.Diagnostics)
+	options := clientOptions(config, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Create a Beeswax Client
-	beeswaxClient := beeswax.NewClient(host, email, password)
-	err := beeswaxClient.Login()
+	var beeswaxClient *beeswax.Client
+	if credentials.APIToken != "" {
+		beeswaxClient, err = beeswax.NewTokenClient(credentials.Host, credentials.APIToken, options)
+	} else {
+		beeswaxClient, err = beeswax.NewClient(credentials.Host, credentials.Email, credentials.Password, options)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Beeswax API Client",
-			"An unexpected error occurred when creating the Beeswax API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
+			"The HTTP transport of the Beeswax API client could not be configured.\n\n"+
 				"Beeswax Client Error: "+err.Error(),
 		)
 		return
 	}
+	beeswaxClient.Retry = retry
+	beeswaxClient.PageSize = int(config.PageSize.ValueInt64())
+	beeswaxClient.SetLimits(config.RequestsPerSecond.ValueFloat64(), int(config.MaxConcurrentRequests.ValueInt64()))
 
 	// Make the Beeswax client available during DataSource and Resource type Configure methods.
 	resp.DataSourceData = beeswaxClient
 	resp.ResourceData = beeswaxClient
 }
 
+// retryPolicy overrides the client default retry policy with the configured values.
+func retryPolicy(config beeswaxProviderModel, diagnostics *diag.Diagnostics) beeswax.RetryPolicy {
+	policy := beeswax.DefaultRetryPolicy()
+	if !config.RetryMaxAttempts.IsNull() && !config.RetryMaxAttempts.IsUnknown() {
+		policy.MaxAttempts = int(config.RetryMaxAttempts.ValueInt64())
+	}
+	parseDuration(diagnostics, "retry_base_backoff", config.RetryBaseBackoff, &policy.BaseBackoff)
+	parseDuration(diagnostics, "retry_max_backoff", config.RetryMaxBackoff, &policy.MaxBackoff)
+	if !config.RetryJitter.IsNull() && !config.RetryJitter.IsUnknown() {
+		policy.Jitter = config.RetryJitter.ValueBool()
+	}
+	return policy
+}
+
+// clientOptions collects the configured HTTP transport settings of the client.
+func clientOptions(config beeswaxProviderModel, diagnostics *diag.Diagnostics) beeswax.Options {
+	options := beeswax.Options{
+		CACertFile:         config.CACertFile.ValueString(),
+		ProxyURL:           config.ProxyURL.ValueString(),
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+		ClientCertFile:     config.ClientCertFile.ValueString(),
+		ClientKeyFile:      config.ClientKeyFile.ValueString(),
+	}
+	parseDuration(diagnostics, "request_timeout", config.RequestTimeout, &options.RequestTimeout)
+	return options
+}
+
+// parseDuration sets target from a configured duration string, leaving it untouched when the value is not set.
+func parseDuration(diagnostics *diag.Diagnostics, attr string, value types.String, target *time.Duration) {
+	if value.IsNull() || value.IsUnknown() {
+		return
+	}
+	d, err := time.ParseDuration(value.ValueString())
+	if err != nil || d <= 0 {
+		diagnostics.AddAttributeError(
+			path.Root(attr),
+			"Invalid Beeswax API "+attr,
+			"The value '"+value.ValueString()+"' is not a valid positive duration, use a value such as \"500ms\" or \"30s\".")
+		return
+	}
+	*target = d
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *beeswaxProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewUserDataSource,
 		NewRoleDataSource,
 		NewRolesDataSource,
+		NewUsersDataSource,
 	}
 }
 
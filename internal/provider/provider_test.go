@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   types.String
+		want    time.Duration
+		wantErr bool
+	}{
+		{types.StringNull(), time.Minute, false},
+		{types.StringUnknown(), time.Minute, false},
+		{types.StringValue("30s"), 30 * time.Second, false},
+		{types.StringValue("500ms"), 500 * time.Millisecond, false},
+		{types.StringValue("0s"), time.Minute, true},
+		{types.StringValue("-1s"), time.Minute, true},
+		{types.StringValue("30"), time.Minute, true},
+	}
+	for _, tt := range tests {
+		var diagnostics diag.Diagnostics
+		got := time.Minute // kept when the value is not set or invalid
+		parseDuration(&diagnostics, "request_timeout", tt.value, &got)
+		if diagnostics.HasError() != tt.wantErr || got != tt.want {
+			t.Errorf("parseDuration(%s) = %s with diagnostics %v, want %s, error %t", tt.value, got, diagnostics, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var diagnostics diag.Diagnostics
+	policy := retryPolicy(beeswaxProviderModel{
+		RetryMaxAttempts: types.Int64Value(2),
+		RetryBaseBackoff: types.StringValue("1s"),
+		RetryMaxBackoff:  types.StringValue("10s"),
+		RetryJitter:      types.BoolValue(false),
+	}, &diagnostics)
+	if diagnostics.HasError() {
+		t.Fatalf("retryPolicy: %v", diagnostics)
+	}
+	if want := (beeswax.RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}); policy != want {
+		t.Errorf("retryPolicy = %+v, want %+v", policy, want)
+	}
+
+	// Without a maximum, a Retry-After header could hold the provider for as long as Beeswax asks
+	retryPolicy(beeswaxProviderModel{RetryMaxBackoff: types.StringValue("0s")}, &diagnostics)
+	if !diagnostics.HasError() {
+		t.Error("expected a zero retry_max_backoff to be rejected")
+	}
+}
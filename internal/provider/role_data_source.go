@@ -4,22 +4,31 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource              = &roleDataSource{}
-	_ datasource.DataSourceWithConfigure = &roleDataSource{}
+	_ datasource.DataSource                     = &roleDataSource{}
+	_ datasource.DataSourceWithConfigure        = &roleDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &roleDataSource{}
 )
 
 type roleDataSource struct {
 	client *beeswax.Client
 }
 
+// roleDataSourceModel adds the lookup options to the role attributes.
+type roleDataSourceModel struct {
+	roleResourceModel
+	ExcludeArchived types.Bool `tfsdk:"exclude_archived"`
+}
+
 func NewRoleDataSource() datasource.DataSource {
 	return &roleDataSource{}
 }
@@ -33,51 +42,79 @@ func (r *roleDataSource) Configure(_ context.Context, req datasource.ConfigureRe
 }
 
 func (d *roleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := roleDataSourceAttributes()
+	attributes["id"] = schema.Int64Attribute{Optional: true, Computed: true, Description: "Unique ID of the role to look up. Conflicts with name."}
+	attributes["name"] = schema.StringAttribute{Optional: true, Computed: true, Description: "Exact name of the role to look up. Conflicts with id."}
+	attributes["exclude_archived"] = schema.BoolAttribute{Optional: true, Description: "Ignore archived roles when looking up by name."}
 	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{
-			"id":                     schema.Int64Attribute{Required: true, Description: "Unique ID of the role"},
-			"name":                   schema.StringAttribute{Computed: true, Description: "Name of the role"}, // TODO: allow getting resource from name
-			"parent_role_id":         schema.Int64Attribute{Computed: true, Description: "The system role that determines which default permissions will be inherited"},
-			"archived":               schema.BoolAttribute{Computed: true, Description: "Archived roles cannot add new users"},
-			"notes":                  schema.StringAttribute{Computed: true, Description: "Free-form notes of up to 255 characters."},
-			"shared_across_accounts": schema.BoolAttribute{Computed: true, Description: "A role that can be shared across accounts, which can be enabled by all-accounts users."},
-			"report_ids":             schema.ListAttribute{Computed: true, ElementType: types.Int64Type, Description: "List of IDs of reports users associated with this role should be able to access. A list of reports may be queried using /reporting/reports."},
-			"permissions": schema.ListNestedAttribute{
-				Computed:    true,
-				Description: "Object containing resource-level permissions for this Role",
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"object_type": schema.StringAttribute{Computed: true, Description: `The name of the resource, e.g. "advertiser" (note, these are singular)`},
-						"permission":  schema.Int64Attribute{Computed: true, Description: "4-bit integer determining Read (1), Create (2), Update (4) and Delete (8) rights for the resource. If a Permission is set to 1, the Role can only Read that type of object. If set to 3, the Role can Read and Create the object (1+2). When a Permission is set to 15 the Role has full rights to the object (1+2+4+8), if set to zero the Role has no rights."},
-					},
+		Attributes: attributes,
+	}
+}
+
+// roleDataSourceAttributes returns the computed attributes of a role, shared by the role data sources.
+func roleDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id":                     schema.Int64Attribute{Computed: true, Description: "Unique ID of the role"},
+		"name":                   schema.StringAttribute{Computed: true, Description: "Name of the role"},
+		"parent_role_id":         schema.Int64Attribute{Computed: true, Description: "The system role that determines which default permissions will be inherited"},
+		"archived":               schema.BoolAttribute{Computed: true, Description: "Archived roles cannot add new users"},
+		"notes":                  schema.StringAttribute{Computed: true, Description: "Free-form notes of up to 255 characters."},
+		"shared_across_accounts": schema.BoolAttribute{Computed: true, Description: "A role that can be shared across accounts, which can be enabled by all-accounts users."},
+		"report_ids":             schema.SetAttribute{Computed: true, ElementType: types.Int64Type, Description: "List of IDs of reports users associated with this role should be able to access. A list of reports may be queried using /reporting/reports."},
+		"permissions": schema.SetNestedAttribute{
+			Computed:    true,
+			Description: "Object containing resource-level permissions for this Role",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"object_type": schema.StringAttribute{Computed: true, Description: `The name of the resource, e.g. "advertiser" (note, these are singular)`},
+					"permission":  schema.Int64Attribute{Computed: true, Description: "4-bit integer determining Read (1), Create (2), Update (4) and Delete (8) rights for the resource. If a Permission is set to 1, the Role can only Read that type of object. If set to 3, the Role can Read and Create the object (1+2). When a Permission is set to 15 the Role has full rights to the object (1+2+4+8), if set to zero the Role has no rights."},
+					"read":        schema.BoolAttribute{Computed: true, Description: "Whether the Role can Read the object"},
+					"create":      schema.BoolAttribute{Computed: true, Description: "Whether the Role can Create the object"},
+					"update":      schema.BoolAttribute{Computed: true, Description: "Whether the Role can Update the object"},
+					"delete":      schema.BoolAttribute{Computed: true, Description: "Whether the Role can Delete the object"},
 				},
 			},
 		},
 	}
 }
 
+// ConfigValidators requires exactly one lookup key.
+func (d *roleDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+	}
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (d *roleDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	// Get current state
-	var state roleResourceModel
+	var state roleDataSourceModel
 	diags := req.Config.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Get role from Beeswax API
-	role, err := d.client.GetRole(state.ID.ValueInt64())
+	// Get role from Beeswax API, by name when no ID is given
+	var role beeswax.Role
+	var err error
+	lookupKey := fmt.Sprintf("ID %d", state.ID.ValueInt64())
+	if state.ID.IsNull() {
+		lookupKey = fmt.Sprintf("named %q", state.Name.ValueString())
+		role, err = findRoleByName(ctx, d.client, state.Name.ValueString(), state.ExcludeArchived.ValueBool())
+	} else {
+		role, err = d.client.Roles().Get(ctx, state.ID.ValueInt64())
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Beeswax role",
-			fmt.Sprintf("Could not read Beeswax role ID %d: %s", state.ID.ValueInt64(), err.Error()),
+			fmt.Sprintf("Could not read Beeswax role %s: %s", lookupKey, err.Error()),
 		)
 		return
 	}
 
 	// Overwrite items with refreshed state
-	fillStateFromRole(&state, role)
+	fillStateFromRole(&state.roleResourceModel, role)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
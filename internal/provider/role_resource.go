@@ -3,19 +3,38 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &roleResource{}
-	_ resource.ResourceWithConfigure = &roleResource{}
+	_ resource.Resource                   = &roleResource{}
+	_ resource.ResourceWithConfigure      = &roleResource{}
+	_ resource.ResourceWithImportState    = &roleResource{}
+	_ resource.ResourceWithIdentity       = &roleResource{}
+	_ resource.ResourceWithValidateConfig = &roleResource{}
+	_ resource.ResourceWithModifyPlan     = &roleResource{}
 )
 
 // roleResource is the resource implementation.
@@ -32,12 +51,40 @@ type roleResourceModel struct {
 	Notes                types.String              `tfsdk:"notes"`
 	SharedAcrossAccounts types.Bool                `tfsdk:"shared_across_accounts"`
 	Permissions          []permissionResourceModel `tfsdk:"permissions"`
-	ReportIDs            []types.Int64             `tfsdk:"report_ids"`
+	ReportIDs            types.Set                 `tfsdk:"report_ids"`
 }
 
 type permissionResourceModel struct {
 	ObjectType types.String `tfsdk:"object_type"`
 	Permission types.Int64  `tfsdk:"permission"`
+	Read       types.Bool   `tfsdk:"read"`
+	Create     types.Bool   `tfsdk:"create"`
+	Update     types.Bool   `tfsdk:"update"`
+	Delete     types.Bool   `tfsdk:"delete"`
+}
+
+// knownObjectTypes lists the object types a Beeswax role can grant permissions on.
+// See https://api-docs.freewheel.tv/beeswax/v2.0/reference for the reference list.
+var knownObjectTypes = []string{
+	"account", "account_group", "activity_log", "advertiser", "alert", "app_list", "bid_modifier", "campaign",
+	"conversion", "creative", "creative_line_item", "custom_list", "deal", "delivery_modifier", "domain_list",
+	"event", "geo_list", "inventory", "ip_list", "key_value", "line_item", "macro", "pixel", "report", "role",
+	"segment", "segment_category", "segment_lookup", "segment_sharing", "segment_upload", "static",
+	"targeting_template", "user", "vendor", "zip_list",
+}
+
+// Bits of the Beeswax permission bitmask.
+const (
+	permissionRead   int64 = 1
+	permissionCreate int64 = 2
+	permissionUpdate int64 = 4
+	permissionDelete int64 = 8
+)
+
+// roleIdentityModel identifies a role in import blocks: by ID, or by name.
+type roleIdentityModel struct {
+	ID   types.Int64  `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
 }
 
 // NewroleResource is a helper function to simplify the provider implementation.
@@ -48,6 +95,8 @@ func NewRoleResource() resource.Resource {
 // Metadata returns the resource type name.
 func (r *roleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_role"
+	// The name is part of the identity and can be changed
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Configure adds the provider configured client to the resource.
@@ -58,21 +107,27 @@ func (r *roleResource) Configure(_ context.Context, req resource.ConfigureReques
 // Schema defines the schema for the resource.
 func (r *roleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Manages a Beeswax role. Permissions and report IDs are sets, their order is not significant. " +
+			"Changes made to the role outside of Terraform are detected on refresh.",
 		Attributes: map[string]schema.Attribute{
 			"id":                     schema.Int64Attribute{Computed: true, Description: "Unique ID of the role"},
-			"name":                   schema.StringAttribute{Required: true, Description: "Name of the role"}, // TODO: allow getting resource from name
-			"parent_role_id":         schema.Int64Attribute{Required: true, Description: "The system role that determines which default permissions will be inherited"},
+			"name":                   schema.StringAttribute{Required: true, Validators: []validator.String{stringvalidator.LengthAtLeast(1)}, Description: "Name of the role"},
+			"parent_role_id":         schema.Int64Attribute{Required: true, Validators: []validator.Int64{int64validator.AtLeast(1)}, Description: "The system role that determines which default permissions will be inherited"},
 			"archived":               schema.BoolAttribute{Optional: true, Computed: true, Default: booldefault.StaticBool(false), Description: "Archived roles cannot add new users"},
-			"notes":                  schema.StringAttribute{Optional: true, Computed: true, Default: stringdefault.StaticString(""), Description: "Free-form notes of up to 255 characters."},
+			"notes":                  schema.StringAttribute{Optional: true, Computed: true, Default: stringdefault.StaticString(""), Validators: []validator.String{stringvalidator.LengthAtMost(255)}, Description: "Free-form notes of up to 255 characters."},
 			"shared_across_accounts": schema.BoolAttribute{Optional: true, Computed: true, Default: booldefault.StaticBool(false), Description: "A role that can be shared across accounts, which can be enabled by all-accounts users."},
-			"report_ids":             schema.ListAttribute{Optional: true, Computed: true, ElementType: types.Int64Type, Description: "List of IDs of reports users associated with this role should be able to access. A list of reports may be queried using /reporting/reports."},
-			"permissions": schema.ListNestedAttribute{
+			"report_ids":             schema.SetAttribute{Optional: true, Computed: true, ElementType: types.Int64Type, PlanModifiers: []planmodifier.Set{setplanmodifier.UseStateForUnknown()}, Validators: []validator.Set{setvalidator.ValueInt64sAre(int64validator.AtLeast(1))}, Description: "List of IDs of reports users associated with this role should be able to access. A list of reports may be queried using /reporting/reports. When not set, the reports Beeswax grants the role are kept."},
+			"permissions": schema.SetNestedAttribute{
 				Required:    true,
 				Description: "Object containing resource-level permissions for this Role",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
-						"object_type": schema.StringAttribute{Required: true, Description: `The name of the resource, e.g. "advertiser" (note, these are singular)`},
-						"permission":  schema.Int64Attribute{Required: true, Description: "4-bit integer determining Read (1), Create (2), Update (4) and Delete (8) rights for the resource. If a Permission is set to 1, the Role can only Read that type of object. If set to 3, the Role can Read and Create the object (1+2). When a Permission is set to 15 the Role has full rights to the object (1+2+4+8), if set to zero the Role has no rights."},
+						"object_type": schema.StringAttribute{Required: true, Validators: []validator.String{stringvalidator.OneOf(knownObjectTypes...)}, Description: `The name of the resource, e.g. "advertiser" (note, these are singular)`},
+						"permission":  schema.Int64Attribute{Optional: true, Computed: true, Validators: []validator.Int64{int64validator.Between(0, 15)}, Description: "4-bit integer determining Read (1), Create (2), Update (4) and Delete (8) rights for the resource. If a Permission is set to 1, the Role can only Read that type of object. If set to 3, the Role can Read and Create the object (1+2). When a Permission is set to 15 the Role has full rights to the object (1+2+4+8), if set to zero the Role has no rights. Either this or the read, create, update and delete flags must be set."},
+						"read":        schema.BoolAttribute{Optional: true, Computed: true, Description: "Whether the Role can Read the object. Alternative to the Read (1) bit of permission."},
+						"create":      schema.BoolAttribute{Optional: true, Computed: true, Description: "Whether the Role can Create the object. Alternative to the Create (2) bit of permission."},
+						"update":      schema.BoolAttribute{Optional: true, Computed: true, Description: "Whether the Role can Update the object. Alternative to the Update (4) bit of permission."},
+						"delete":      schema.BoolAttribute{Optional: true, Computed: true, Description: "Whether the Role can Delete the object. Alternative to the Delete (8) bit of permission."},
 					},
 				},
 			},
@@ -80,6 +135,113 @@ func (r *roleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 	}
 }
 
+// IdentitySchema defines the identity used by import blocks and config generation.
+func (r *roleResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id":   identityschema.Int64Attribute{OptionalForImport: true, Description: "Unique ID of the role"},
+			"name": identityschema.StringAttribute{OptionalForImport: true, Description: "Exact name of the role, used when id is not set"},
+		},
+	}
+}
+
+// ValidateConfig checks that every permission is given either as a bitmask or as flags, and that both forms agree.
+func (r *roleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	objects, permissions, _, diags := permissionObjects(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i, p := range permissions {
+		flagsSet, flagsUnknown := permissionFlagsState(p)
+		switch {
+		case p.Permission.IsNull() && !flagsSet:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("permissions").AtSetValue(objects[i]),
+				"Missing permission",
+				fmt.Sprintf("Permission for %s must set either permission or the read, create, update and delete flags.", p.ObjectType.String()),
+			)
+		case !p.Permission.IsNull() && !p.Permission.IsUnknown() && flagsSet && !flagsUnknown &&
+			p.Permission.ValueInt64() != permissionFromFlags(p):
+			resp.Diagnostics.AddAttributeError(
+				path.Root("permissions").AtSetValue(objects[i]),
+				"Conflicting permission",
+				fmt.Sprintf("Permission for %s is set to %d, which does not match its read, create, update and delete flags.", p.ObjectType.String(), p.Permission.ValueInt64()),
+			)
+		}
+	}
+}
+
+// ModifyPlan resolves the permission values left to the provider: the bitmask of permissions given as flags,
+// and the flags of permissions given as a bitmask, unset flags being false. Set elements are identified by
+// their whole value, so once known in the plan they match the prior state and do not show as replaced when
+// another attribute of the role is edited.
+func (r *roleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	_, configured, known, diags := permissionObjects(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !known {
+		return // resolved at apply
+	}
+
+	resolved := []permissionResourceModel{}
+	for _, p := range configured {
+		resolved = append(resolved, resolvePermission(p))
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("permissions"), resolved)...)
+}
+
+// permissionObjects returns the permissions of the configuration along with their set elements.
+// known is false when the set, one of its elements or one of their values is unknown.
+func permissionObjects(ctx context.Context, config tfsdk.Config) (objects []types.Object, permissions []permissionResourceModel, known bool, diags diag.Diagnostics) {
+	var set types.Set
+	diags = config.GetAttribute(ctx, path.Root("permissions"), &set)
+	if diags.HasError() || set.IsNull() || set.IsUnknown() {
+		return nil, nil, false, diags
+	}
+	known = true
+	for _, element := range set.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsNull() || object.IsUnknown() {
+			known = false
+			continue
+		}
+		var p permissionResourceModel
+		diags.Append(object.As(ctx, &p, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil, nil, false, diags
+		}
+		_, flagsUnknown := permissionFlagsState(p)
+		known = known && !p.ObjectType.IsUnknown() && !p.Permission.IsUnknown() && !flagsUnknown
+		objects = append(objects, object)
+		permissions = append(permissions, p)
+	}
+	return objects, permissions, known, diags
+}
+
+// permissionFlagsState reports whether any read, create, update and delete flag is set, and whether any is unknown.
+func permissionFlagsState(p permissionResourceModel) (set bool, unknown bool) {
+	for _, flag := range []types.Bool{p.Read, p.Create, p.Update, p.Delete} {
+		set = set || !flag.IsNull()
+		unknown = unknown || flag.IsUnknown()
+	}
+	return set, unknown
+}
+
+// resolvePermission fills the bitmask or the flags left unset in a configured permission.
+func resolvePermission(p permissionResourceModel) permissionResourceModel {
+	permission := p.Permission.ValueInt64()
+	if p.Permission.IsNull() || p.Permission.IsUnknown() {
+		permission = permissionFromFlags(p)
+	}
+	resolved := permissionModel(permission)
+	resolved.ObjectType = p.ObjectType
+	return resolved
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
@@ -92,21 +254,20 @@ func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	// Create new role
 	role := convertToRole(plan)
-	roleId, err := r.client.CreateRole(role)
+	created, err := r.client.Roles().Create(ctx, role)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating role",
-			"Could not create role, unexpected error: "+err.Error(),
-		)
+		addClientError(&resp.Diagnostics, "Error creating role", "Could not create role, unexpected error: ", err)
 		return
 	}
 
-	// Map response body to schema and populate Computed attribute values
-	plan.ID = types.Int64Value(roleId)
+	// Populate Computed attribute values, the configured ones are kept as planned
+	plan.ID = types.Int64Value(created.ID)
+	completeRole(&plan, created)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setRoleIdentity(ctx, resp.Identity, plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -123,7 +284,10 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	// Get role from Beeswax API
-	role, err := r.client.GetRole(state.ID.ValueInt64())
+	role, err := r.client.Roles().Get(ctx, state.ID.ValueInt64())
+	if removeIfNotFound(ctx, err, resp) {
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Beeswax role",
@@ -138,6 +302,7 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setRoleIdentity(ctx, resp.Identity, state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -156,23 +321,28 @@ func (r *roleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	// Update role
-	role := convertToRole(plan)
-	role.ID = state.ID.ValueInt64()
-	err := r.client.UpdateRole(role)
+	// Update role, only sending the attributes that changed so that fields managed outside of Terraform are kept
+	fields, err := changedFields(convertToRole(state), convertToRole(plan))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating role",
-			"Could not update role, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.AddError("Error updating role", "Could not compute role changes: "+err.Error())
 		return
 	}
+	updated := convertToRole(state)
+	if len(fields) > 0 {
+		updated, err = r.client.Roles().Patch(ctx, state.ID.ValueInt64(), fields)
+		if err != nil {
+			addClientError(&resp.Diagnostics, "Error updating role", "Could not update role, unexpected error: ", err)
+			return
+		}
+	}
 
 	plan.ID = state.ID // Keep the same ID
+	completeRole(&plan, updated)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setRoleIdentity(ctx, resp.Identity, plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -189,8 +359,8 @@ func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 
 	// Delete role
-	err := r.client.DeleteRole(plan.ID.ValueInt64())
-	if err != nil {
+	err := r.client.Roles().Delete(ctx, plan.ID.ValueInt64())
+	if err != nil && !beeswax.IsNotFound(err) { // already deleted outside of Terraform
 		resp.Diagnostics.AddError(
 			"Error deleting role",
 			"Could not delete role, unexpected error: "+err.Error(),
@@ -199,40 +369,176 @@ func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 }
 
+// completeRole fills the computed values of a planned role: the permission values left to the provider,
+// and the report IDs when they are not configured, taken from the role returned by Beeswax.
+// Configured values are kept as planned, changes made by Beeswax show up on the next refresh.
+func completeRole(plan *roleResourceModel, role beeswax.Role) {
+	permissions := []permissionResourceModel{}
+	for _, p := range plan.Permissions {
+		permissions = append(permissions, resolvePermission(p))
+	}
+	plan.Permissions = permissions
+	if plan.ReportIDs.IsUnknown() {
+		plan.ReportIDs = reportIDsValue(role.ReportIDs)
+	}
+}
+
+// ImportState imports an existing role either by numeric ID or by name,
+// given as import identifier or through the identity of an import block.
+func (r *roleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var identity roleIdentityModel
+	if req.ID != "" {
+		if roleID, err := strconv.ParseInt(req.ID, 10, 64); err == nil {
+			identity.ID = types.Int64Value(roleID)
+		} else {
+			identity.Name = types.StringValue(req.ID)
+		}
+	} else if req.Identity != nil {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if identity.ID.IsNull() {
+		if identity.Name.IsNull() {
+			resp.Diagnostics.AddError("Invalid import identity", "Either the id or the name of the role must be set.")
+			return
+		}
+		role, err := findRoleByName(ctx, r.client, identity.Name.ValueString(), false)
+		if err != nil {
+			resp.Diagnostics.AddError("Error importing Beeswax role", err.Error())
+			return
+		}
+		identity.ID = types.Int64Value(role.ID)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+}
+
+// findRoleByName returns the only role having exactly the given name, optionally ignoring archived roles.
+func findRoleByName(ctx context.Context, client *beeswax.Client, name string, excludeArchived bool) (beeswax.Role, error) {
+	filter := beeswax.RoleFilter{Name: name}
+	if excludeArchived {
+		archived := false
+		filter.Archived = &archived
+	}
+	roles, err := client.GetRoles(ctx, filter)
+	if err != nil {
+		return beeswax.Role{}, fmt.Errorf("could not search Beeswax roles by name %q: %w", name, err)
+	}
+	matches := []beeswax.Role{}
+	for _, role := range roles {
+		if role.Name == name && !(excludeArchived && role.Archived) {
+			matches = append(matches, role)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return beeswax.Role{}, fmt.Errorf("no Beeswax role found with name %q", name)
+	case 1:
+		return matches[0], nil
+	}
+	ids := []string{}
+	for _, role := range matches {
+		ids = append(ids, strconv.FormatInt(role.ID, 10))
+	}
+	return beeswax.Role{}, fmt.Errorf("%d Beeswax roles found with name %q (IDs %s), use the ID instead", len(matches), name, strings.Join(ids, ", "))
+}
+
+// setRoleIdentity records the identity of the role when Terraform supports it.
+func setRoleIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state roleResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, roleIdentityModel{ID: state.ID, Name: state.Name})
+}
+
 func convertToRole(plan roleResourceModel) beeswax.Role {
 	permissions := []beeswax.Permission{}
 	for _, p := range plan.Permissions {
+		permission := p.Permission.ValueInt64()
+		if p.Permission.IsNull() || p.Permission.IsUnknown() {
+			permission = permissionFromFlags(p)
+		}
 		permissions = append(permissions, beeswax.Permission{
 			ObjectType: p.ObjectType.ValueString(),
-			Permission: p.Permission.ValueInt64(),
+			Permission: permission,
 		})
 	}
+	// Sets have no order, sort them so that equal sets convert to equal roles
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].ObjectType < permissions[j].ObjectType })
+	// Report IDs left unknown until apply are sent as an empty set, Beeswax may grant some on its own
+	reportIDs := []int64{}
+	for _, id := range plan.ReportIDs.Elements() {
+		if id, ok := id.(types.Int64); ok {
+			reportIDs = append(reportIDs, id.ValueInt64())
+		}
+	}
+	sort.Slice(reportIDs, func(i, j int) bool { return reportIDs[i] < reportIDs[j] })
 	return beeswax.Role{
 		ID:                   plan.ID.ValueInt64(),
 		Name:                 plan.Name.ValueString(),
-		ParentRoleID:         plan.ParentRoleID.ValueInt64(),
+		ParentRoleID:         plan.ParentRoleID.ValueInt64Pointer(),
 		Archived:             plan.Archived.ValueBool(),
 		Notes:                plan.Notes.ValueString(),
 		SharedAcrossAccounts: plan.SharedAcrossAccounts.ValueBool(),
 		Permissions:          permissions,
-		ReportIDs:            convertListInt(plan.ReportIDs),
+		ReportIDs:            reportIDs,
+	}
+}
+
+// permissionFromFlags converts the read, create, update and delete flags to the Beeswax bitmask.
+func permissionFromFlags(p permissionResourceModel) int64 {
+	permission := int64(0)
+	if p.Read.ValueBool() {
+		permission |= permissionRead
+	}
+	if p.Create.ValueBool() {
+		permission |= permissionCreate
+	}
+	if p.Update.ValueBool() {
+		permission |= permissionUpdate
+	}
+	if p.Delete.ValueBool() {
+		permission |= permissionDelete
+	}
+	return permission
+}
+
+// permissionModel returns both forms of a Beeswax permission bitmask, without object type.
+func permissionModel(permission int64) permissionResourceModel {
+	return permissionResourceModel{
+		Permission: types.Int64Value(permission),
+		Read:       types.BoolValue(permission&permissionRead != 0),
+		Create:     types.BoolValue(permission&permissionCreate != 0),
+		Update:     types.BoolValue(permission&permissionUpdate != 0),
+		Delete:     types.BoolValue(permission&permissionDelete != 0),
 	}
 }
 
 func fillStateFromRole(state *roleResourceModel, role beeswax.Role) {
 	state.ID = types.Int64Value(role.ID)
 	state.Name = types.StringValue(role.Name)
-	state.ParentRoleID = types.Int64Value(role.ParentRoleID)
+	state.ParentRoleID = types.Int64PointerValue(role.ParentRoleID)
 	state.Archived = types.BoolValue(role.Archived)
 	state.Notes = types.StringValue(role.Notes)
 	state.SharedAcrossAccounts = types.BoolValue(role.SharedAcrossAccounts)
 	permissions := []permissionResourceModel{}
-
 	for _, p := range role.Permissions {
-		permissions = append(permissions, permissionResourceModel{
-			ObjectType: types.StringValue(p.ObjectType),
-			Permission: types.Int64Value(p.Permission),
-		})
+		permission := permissionModel(p.Permission)
+		permission.ObjectType = types.StringValue(p.ObjectType)
+		permissions = append(permissions, permission)
 	}
 	state.Permissions = permissions
+	state.ReportIDs = reportIDsValue(role.ReportIDs)
+}
+
+// reportIDsValue converts the report IDs of a Beeswax role to a set value.
+func reportIDsValue(ids []int64) types.Set {
+	reportIDs := []attr.Value{}
+	for _, id := range ids {
+		reportIDs = append(reportIDs, types.Int64Value(id))
+	}
+	return types.SetValueMust(types.Int64Type, reportIDs)
 }
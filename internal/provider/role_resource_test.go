@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
+)
+
+func TestPermissionFromFlags(t *testing.T) {
+	tests := []struct {
+		p    permissionResourceModel
+		want int64
+	}{
+		{permissionResourceModel{}, 0},
+		{permissionResourceModel{Read: types.BoolValue(true)}, 1},
+		{permissionResourceModel{Read: types.BoolValue(true), Create: types.BoolValue(true)}, 3},
+		{permissionResourceModel{Read: types.BoolValue(true), Update: types.BoolValue(true), Delete: types.BoolValue(true), Create: types.BoolValue(false)}, 13},
+		{permissionResourceModel{Read: types.BoolValue(true), Create: types.BoolValue(true), Update: types.BoolValue(true), Delete: types.BoolValue(true)}, 15},
+	}
+	for _, tt := range tests {
+		if got := permissionFromFlags(tt.p); got != tt.want {
+			t.Errorf("permissionFromFlags(%+v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestPermissionModelRoundTrip(t *testing.T) {
+	for permission := int64(0); permission <= 15; permission++ {
+		model := permissionModel(permission)
+		if got := permissionFromFlags(model); got != permission {
+			t.Errorf("permissionFromFlags(permissionModel(%d)) = %d", permission, got)
+		}
+	}
+}
+
+// TestRoleModifyPlanResolvesPermissions checks that the values computed from either permission form are
+// known in the plan and equal to the state written after apply, so editing another attribute of a role
+// does not show its permissions as replaced.
+func TestRoleModifyPlanResolvesPermissions(t *testing.T) {
+	ctx := context.Background()
+	r := &roleResource{}
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	role := func(numeric, flags permissionResourceModel) roleResourceModel {
+		return roleResourceModel{
+			ID:                   types.Int64Unknown(),
+			Name:                 types.StringValue("my_role"),
+			ParentRoleID:         types.Int64Value(1),
+			Archived:             types.BoolValue(false),
+			Notes:                types.StringValue("edited"),
+			SharedAcrossAccounts: types.BoolValue(false),
+			Permissions:          []permissionResourceModel{numeric, flags},
+			ReportIDs:            reportIDsValue(nil),
+		}
+	}
+	config := tfsdk.State{Schema: schemaResp.Schema}
+	diags := config.Set(ctx, role(
+		permissionResourceModel{ObjectType: types.StringValue("account"), Permission: types.Int64Value(13), Read: types.BoolNull(), Create: types.BoolNull(), Update: types.BoolNull(), Delete: types.BoolNull()},
+		permissionResourceModel{ObjectType: types.StringValue("creative"), Permission: types.Int64Null(), Read: types.BoolValue(true), Create: types.BoolNull(), Update: types.BoolValue(true), Delete: types.BoolValue(true)},
+	))
+	plan := tfsdk.State{Schema: schemaResp.Schema}
+	diags.Append(plan.Set(ctx, role(
+		permissionResourceModel{ObjectType: types.StringValue("account"), Permission: types.Int64Value(13), Read: types.BoolUnknown(), Create: types.BoolUnknown(), Update: types.BoolUnknown(), Delete: types.BoolUnknown()},
+		permissionResourceModel{ObjectType: types.StringValue("creative"), Permission: types.Int64Unknown(), Read: types.BoolValue(true), Create: types.BoolUnknown(), Update: types.BoolValue(true), Delete: types.BoolValue(true)},
+	))...)
+	if diags.HasError() {
+		t.Fatalf("building the plan: %v", diags)
+	}
+
+	req := resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan.Raw},
+	}
+	resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("ModifyPlan: %v", resp.Diagnostics)
+	}
+
+	var modified roleResourceModel
+	if diags := resp.Plan.Get(ctx, &modified); diags.HasError() {
+		t.Fatalf("reading the plan: %v", diags)
+	}
+	// The state of the role as read from Beeswax on the next refresh
+	applied := roleResourceModel{}
+	fillStateFromRole(&applied, beeswax.Role{Permissions: []beeswax.Permission{{ObjectType: "account", Permission: 13}, {ObjectType: "creative", Permission: 13}}})
+	if len(modified.Permissions) != 2 {
+		t.Fatalf("planned permissions = %+v, want 2 elements", modified.Permissions)
+	}
+	for _, p := range modified.Permissions {
+		if !slices.Contains(applied.Permissions, p) {
+			t.Errorf("planned permission %+v does not match the applied permissions %+v", p, applied.Permissions)
+		}
+	}
+}
+
+func TestRoleModifyPlanKeepsUnknownPermissions(t *testing.T) {
+	ctx := context.Background()
+	r := &roleResource{}
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	model := roleResourceModel{
+		ID:           types.Int64Unknown(),
+		Name:         types.StringValue("my_role"),
+		ParentRoleID: types.Int64Value(1),
+		Permissions: []permissionResourceModel{
+			{ObjectType: types.StringValue("account"), Permission: types.Int64Unknown(), Read: types.BoolNull(), Create: types.BoolNull(), Update: types.BoolNull(), Delete: types.BoolNull()},
+		},
+		ReportIDs: reportIDsValue(nil),
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		t.Fatalf("building the plan: %v", diags)
+	}
+	req := resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: state.Raw},
+	}
+	resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("ModifyPlan: %v", resp.Diagnostics)
+	}
+	if !resp.Plan.Raw.Equal(req.Plan.Raw) {
+		t.Error("a permission unknown until apply must be left to apply")
+	}
+}
+
+// TestRoleCreateKeepsPlannedValues checks that the state written by Create matches the plan, even when
+// Beeswax returns a role differing from it, and that report IDs unknown until apply are taken from Beeswax.
+func TestRoleCreateKeepsPlannedValues(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/v2/authenticate":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/v2/roles":
+			parent := int64(1)
+			_ = json.NewEncoder(w).Encode(beeswax.Role{
+				ID:           42,
+				Name:         "my_role",
+				ParentRoleID: &parent,
+				Notes:        "set by Beeswax",
+				Permissions:  []beeswax.Permission{{ObjectType: "account", Permission: 15}, {ObjectType: "report", Permission: 1}},
+				ReportIDs:    []int64{5},
+			})
+		default:
+			t.Errorf("unexpected call %s %s, the role must not be read back", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client, err := beeswax.NewClient(server.URL, "user@example.com", "secret", beeswax.Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	client.Retry.MaxAttempts = 1
+
+	r := &roleResource{client: client}
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	plan := roleResourceModel{
+		ID:                   types.Int64Unknown(),
+		Name:                 types.StringValue("my_role"),
+		ParentRoleID:         types.Int64Value(1),
+		Archived:             types.BoolValue(false),
+		Notes:                types.StringValue(""),
+		SharedAcrossAccounts: types.BoolValue(false),
+		Permissions: []permissionResourceModel{
+			{ObjectType: types.StringValue("account"), Permission: types.Int64Unknown(), Read: types.BoolValue(true), Create: types.BoolUnknown(), Update: types.BoolValue(true), Delete: types.BoolValue(true)},
+		},
+		ReportIDs: types.SetUnknown(types.Int64Type),
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, plan); diags.HasError() {
+		t.Fatalf("building the plan: %v", diags)
+	}
+
+	req := resource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: state.Raw}}
+	resp := &resource.CreateResponse{State: state}
+	r.Create(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsFullyKnown() {
+		t.Fatal("the state written by Create must be fully known")
+	}
+
+	var created roleResourceModel
+	if diags := resp.State.Get(ctx, &created); diags.HasError() {
+		t.Fatalf("reading the state: %v", diags)
+	}
+	if created.ID.ValueInt64() != 42 {
+		t.Errorf("id = %s, want 42", created.ID)
+	}
+	if created.Notes.ValueString() != "" {
+		t.Errorf("notes = %s, want the planned empty notes", created.Notes)
+	}
+	account := permissionModel(13)
+	account.ObjectType = types.StringValue("account")
+	if !slices.Equal(created.Permissions, []permissionResourceModel{account}) {
+		t.Errorf("permissions = %+v, want the planned %+v", created.Permissions, account)
+	}
+	if !created.ReportIDs.Equal(reportIDsValue([]int64{5})) {
+		t.Errorf("report_ids = %s, want the IDs returned by Beeswax [5]", created.ReportIDs)
+	}
+}
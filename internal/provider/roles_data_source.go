@@ -3,9 +3,11 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
 )
@@ -24,14 +26,13 @@ func NewRolesDataSource() datasource.DataSource {
 	return &rolesDataSource{}
 }
 
-// roleResourceModel is the data the resource manipulates.
+// rolesResourceModel is the data the data source manipulates.
 type rolesResourceModel struct {
-	Roles []liteRoleResourceModel `tfsdk:"roles"`
-}
-
-type liteRoleResourceModel struct {
-	ID   types.Int64  `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	NameRegex            types.String        `tfsdk:"name_regex"`
+	Archived             types.Bool          `tfsdk:"archived"`
+	SharedAcrossAccounts types.Bool          `tfsdk:"shared_across_accounts"`
+	ParentRoleID         types.Int64         `tfsdk:"parent_role_id"`
+	Roles                []roleResourceModel `tfsdk:"roles"`
 }
 
 func (d *rolesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -45,14 +46,15 @@ func (r *rolesDataSource) Configure(_ context.Context, req datasource.ConfigureR
 func (d *rolesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"name_regex":             schema.StringAttribute{Optional: true, Description: "Only keep roles whose name matches this regular expression (RE2 syntax)."},
+			"archived":               schema.BoolAttribute{Optional: true, Description: "Only keep archived roles when true, non-archived roles when false."},
+			"shared_across_accounts": schema.BoolAttribute{Optional: true, Description: "Only keep roles shared across accounts when true, not shared when false."},
+			"parent_role_id":         schema.Int64Attribute{Optional: true, Description: "Only keep roles inheriting from this parent role."},
 			"roles": schema.ListNestedAttribute{
 				Computed:    true,
 				Description: "List of Role available on Beeswax API",
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"id":   schema.Int64Attribute{Computed: true, Description: "Unique ID of the role"},
-						"name": schema.StringAttribute{Computed: true, Description: "Name of the role"},
-					},
+					Attributes: roleDataSourceAttributes(),
 				},
 			},
 		},
@@ -69,8 +71,24 @@ func (d *rolesDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	// Filters are sent to the API and checked again locally
+	filter := beeswax.RoleFilter{
+		Archived:             state.Archived.ValueBoolPointer(),
+		SharedAcrossAccounts: state.SharedAcrossAccounts.ValueBoolPointer(),
+		ParentRoleID:         state.ParentRoleID.ValueInt64(),
+	}
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid name_regex", err.Error())
+			return
+		}
+	}
+
 	// Get role from Beeswax API
-	roles, err := d.client.GetRoles()
+	roles, err := d.client.GetRoles(ctx, filter)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Beeswax role",
@@ -80,11 +98,17 @@ func (d *rolesDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	// Overwrite items with refreshed state
+	state.Roles = []roleResourceModel{}
 	for _, role := range roles {
-		state.Roles = append(state.Roles, liteRoleResourceModel{
-			ID:   types.Int64Value(role.ID),
-			Name: types.StringValue(role.Name),
-		})
+		if (filter.Archived != nil && role.Archived != *filter.Archived) ||
+			(filter.SharedAcrossAccounts != nil && role.SharedAcrossAccounts != *filter.SharedAcrossAccounts) ||
+			(filter.ParentRoleID != 0 && (role.ParentRoleID == nil || *role.ParentRoleID != filter.ParentRoleID)) ||
+			(nameRegex != nil && !nameRegex.MatchString(role.Name)) {
+			continue
+		}
+		var model roleResourceModel
+		fillStateFromRole(&model, role)
+		state.Roles = append(state.Roles, model)
 	}
 
 	// Set refreshed state
@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource              = &userDataSource{}
-	_ datasource.DataSourceWithConfigure = &userDataSource{}
+	_ datasource.DataSource                     = &userDataSource{}
+	_ datasource.DataSourceWithConfigure        = &userDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &userDataSource{}
 )
 
 type userDataSource struct {
@@ -33,19 +37,34 @@ func (r *userDataSource) Configure(_ context.Context, req datasource.ConfigureRe
 }
 
 func (d *userDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := userDataSourceAttributes()
+	attributes["id"] = schema.Int64Attribute{Optional: true, Computed: true, Description: "ID of the user to look up. Conflicts with email."}
+	attributes["email"] = schema.StringAttribute{Optional: true, Computed: true, Description: "Email of the user to look up, compared case-insensitively. Conflicts with id."}
 	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{
-			"id":                 schema.Int64Attribute{Required: true},
-			"email":              schema.StringAttribute{Computed: true}, // TODO: allow getting resource from email
-			"first_name":         schema.StringAttribute{Computed: true},
-			"last_name":          schema.StringAttribute{Computed: true},
-			"role_id":            schema.Int64Attribute{Computed: true},
-			"account_group_ids":  schema.ListAttribute{Computed: true, ElementType: types.Int64Type},
-			"account_id":         schema.Int64Attribute{Computed: true},
-			"active":             schema.BoolAttribute{Computed: true},
-			"super_user":         schema.BoolAttribute{Computed: true},
-			"all_account_access": schema.BoolAttribute{Computed: true},
-		},
+		Attributes: attributes,
+	}
+}
+
+// userDataSourceAttributes returns the computed attributes of a user, shared by the user data sources.
+func userDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id":                 schema.Int64Attribute{Computed: true},
+		"email":              schema.StringAttribute{Computed: true},
+		"first_name":         schema.StringAttribute{Computed: true},
+		"last_name":          schema.StringAttribute{Computed: true},
+		"role_id":            schema.Int64Attribute{Computed: true},
+		"account_group_ids":  schema.ListAttribute{Computed: true, ElementType: types.Int64Type},
+		"account_id":         schema.Int64Attribute{Computed: true},
+		"active":             schema.BoolAttribute{Computed: true},
+		"super_user":         schema.BoolAttribute{Computed: true},
+		"all_account_access": schema.BoolAttribute{Computed: true},
+	}
+}
+
+// ConfigValidators requires exactly one lookup key.
+func (d *userDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("email")),
 	}
 }
 
@@ -59,18 +78,28 @@ func (d *userDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	// Get user from Beeswax API
-	user, err := d.client.GetUser(state.ID.ValueInt64())
+	// Get user from Beeswax API, by email when no ID is given
+	var user beeswax.User
+	var err error
+	if state.ID.IsNull() {
+		user, err = findUserByEmail(ctx, d.client, state.Email.ValueString())
+	} else {
+		user, err = d.client.Users().Get(ctx, state.ID.ValueInt64())
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Beeswax User",
-			fmt.Sprintf("Could not read Beeswax User ID %d: %s", state.ID.ValueInt64(), err.Error()),
+			fmt.Sprintf("Could not read Beeswax User %s: %s", userLookupKey(state), err.Error()),
 		)
 		return
 	}
 
-	// Overwrite items with refreshed state
+	// Overwrite items with refreshed state, keeping the configured email casing
+	email := state.Email
 	fillStateFromUser(&state, user)
+	if !email.IsNull() {
+		state.Email = email
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -79,3 +108,11 @@ func (d *userDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 }
+
+// userLookupKey describes how the user was looked up, for error messages.
+func userLookupKey(state userResourceModel) string {
+	if state.ID.IsNull() {
+		return "with email " + state.Email.ValueString()
+	}
+	return fmt.Sprintf("ID %d", state.ID.ValueInt64())
+}
@@ -3,18 +3,31 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &userResource{}
-	_ resource.ResourceWithConfigure = &userResource{}
+	_ resource.Resource                = &userResource{}
+	_ resource.ResourceWithConfigure   = &userResource{}
+	_ resource.ResourceWithImportState = &userResource{}
+	_ resource.ResourceWithIdentity    = &userResource{}
 )
 
 // userResource is the resource implementation.
@@ -36,6 +49,15 @@ type userResourceModel struct {
 	AllAccountAccess types.Bool    `tfsdk:"all_account_access"`
 }
 
+// emailRegexp only rejects obviously malformed emails, the API performs the complete check.
+var emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// userIdentityModel identifies a user in import blocks: by ID, or by email.
+type userIdentityModel struct {
+	ID    types.Int64  `tfsdk:"id"`
+	Email types.String `tfsdk:"email"`
+}
+
 // NewUserResource is a helper function to simplify the provider implementation.
 func NewUserResource() resource.Resource {
 	return &userResource{}
@@ -44,6 +66,8 @@ func NewUserResource() resource.Resource {
 // Metadata returns the resource type name.
 func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_user"
+	// The email is part of the identity and can be changed
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Configure adds the provider configured client to the resource.
@@ -56,12 +80,12 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id":                 schema.Int64Attribute{Computed: true},
-			"email":              schema.StringAttribute{Required: true},
-			"first_name":         schema.StringAttribute{Required: true},
-			"last_name":          schema.StringAttribute{Required: true},
-			"role_id":            schema.Int64Attribute{Required: true},
-			"account_group_ids":  schema.ListAttribute{Required: true, ElementType: types.Int64Type},
-			"account_id":         schema.Int64Attribute{Optional: true},
+			"email":              schema.StringAttribute{Required: true, Validators: []validator.String{stringvalidator.RegexMatches(emailRegexp, "must be a valid email address")}},
+			"first_name":         schema.StringAttribute{Required: true, Validators: []validator.String{stringvalidator.LengthAtLeast(1)}},
+			"last_name":          schema.StringAttribute{Required: true, Validators: []validator.String{stringvalidator.LengthAtLeast(1)}},
+			"role_id":            schema.Int64Attribute{Required: true, Validators: []validator.Int64{int64validator.AtLeast(1)}},
+			"account_group_ids":  schema.ListAttribute{Required: true, ElementType: types.Int64Type, Validators: []validator.List{listvalidator.ValueInt64sAre(int64validator.AtLeast(1))}},
+			"account_id":         schema.Int64Attribute{Optional: true, Validators: []validator.Int64{int64validator.AtLeast(1)}},
 			"active":             schema.BoolAttribute{Optional: true, Computed: true, Default: booldefault.StaticBool(true)},
 			"super_user":         schema.BoolAttribute{Optional: true, Computed: true, Default: booldefault.StaticBool(false)},
 			"all_account_access": schema.BoolAttribute{Optional: true, Computed: true, Default: booldefault.StaticBool(false)},
@@ -69,6 +93,16 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 	}
 }
 
+// IdentitySchema defines the identity used by import blocks and config generation.
+func (r *userResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id":    identityschema.Int64Attribute{OptionalForImport: true, Description: "Unique ID of the user"},
+			"email": identityschema.StringAttribute{OptionalForImport: true, Description: "Email of the user, used when id is not set"},
+		},
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
@@ -81,21 +115,19 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	// Create new user
 	user := convertToUser(plan)
-	userId, err := r.client.CreateUser(user)
+	created, err := r.client.Users().Create(ctx, user)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating user",
-			"Could not create user, unexpected error: "+err.Error(),
-		)
+		addClientError(&resp.Diagnostics, "Error creating user", "Could not create user, unexpected error: ", err)
 		return
 	}
 
 	// Map response body to schema and populate Computed attribute values
-	plan.ID = types.Int64Value(userId)
+	plan.ID = types.Int64Value(created.ID)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setUserIdentity(ctx, resp.Identity, plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -112,7 +144,10 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	// Get user from Beeswax API
-	user, err := r.client.GetUser(state.ID.ValueInt64())
+	user, err := r.client.Users().Get(ctx, state.ID.ValueInt64())
+	if removeIfNotFound(ctx, err, resp) {
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Beeswax User",
@@ -127,6 +162,7 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setUserIdentity(ctx, resp.Identity, state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -145,23 +181,26 @@ func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	// Update user
-	user := convertToUser(plan)
-	user.ID = state.ID.ValueInt64()
-	err := r.client.UpdateUser(user)
+	// Update user, only sending the attributes that changed so that fields managed outside of Terraform are kept
+	fields, err := changedFields(convertToUser(state), convertToUser(plan))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating user",
-			"Could not update user, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.AddError("Error updating user", "Could not compute user changes: "+err.Error())
 		return
 	}
+	if len(fields) > 0 {
+		_, err = r.client.Users().Patch(ctx, state.ID.ValueInt64(), fields)
+		if err != nil {
+			addClientError(&resp.Diagnostics, "Error updating user", "Could not update user, unexpected error: ", err)
+			return
+		}
+	}
 
 	plan.ID = state.ID // Keep the same ID
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setUserIdentity(ctx, resp.Identity, plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -178,8 +217,8 @@ func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 
 	// Delete user
-	err := r.client.DeleteUser(plan.ID.ValueInt64())
-	if err != nil {
+	err := r.client.Users().Delete(ctx, plan.ID.ValueInt64())
+	if err != nil && !beeswax.IsNotFound(err) { // already deleted outside of Terraform
 		resp.Diagnostics.AddError(
 			"Error deleting user",
 			"Could not delete user, unexpected error: "+err.Error(),
@@ -188,6 +227,78 @@ func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 }
 
+// ImportState imports an existing user either by numeric ID or by email,
+// given as import identifier or through the identity of an import block.
+func (r *userResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var identity userIdentityModel
+	if req.ID != "" {
+		if userID, err := strconv.ParseInt(req.ID, 10, 64); err == nil {
+			identity.ID = types.Int64Value(userID)
+		} else if strings.Contains(req.ID, "@") {
+			identity.Email = types.StringValue(req.ID)
+		} else {
+			resp.Diagnostics.AddError(
+				"Invalid import identifier",
+				fmt.Sprintf("Expected a numeric user ID or an email, got %q.", req.ID),
+			)
+			return
+		}
+	} else if req.Identity != nil {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if identity.ID.IsNull() {
+		if identity.Email.IsNull() {
+			resp.Diagnostics.AddError("Invalid import identity", "Either the id or the email of the user must be set.")
+			return
+		}
+		user, err := findUserByEmail(ctx, r.client, identity.Email.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error importing Beeswax User", err.Error())
+			return
+		}
+		identity.ID = types.Int64Value(user.ID)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+}
+
+// findUserByEmail returns the only user having the given email, compared case-insensitively.
+func findUserByEmail(ctx context.Context, client *beeswax.Client, email string) (beeswax.User, error) {
+	users, err := client.GetUsers(ctx, beeswax.UserFilter{Email: email})
+	if err != nil {
+		return beeswax.User{}, fmt.Errorf("could not search Beeswax users by email %q: %w", email, err)
+	}
+	matches := []beeswax.User{}
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) {
+			matches = append(matches, user)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return beeswax.User{}, fmt.Errorf("no Beeswax user found with email %q", email)
+	case 1:
+		return matches[0], nil
+	}
+	ids := []string{}
+	for _, user := range matches {
+		ids = append(ids, strconv.FormatInt(user.ID, 10))
+	}
+	return beeswax.User{}, fmt.Errorf("%d Beeswax users found with email %q (IDs %s), use the ID instead", len(matches), email, strings.Join(ids, ", "))
+}
+
+// setUserIdentity records the identity of the user when Terraform supports it.
+func setUserIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state userResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, userIdentityModel{ID: state.ID, Email: state.Email})
+}
+
 func convertToUser(plan userResourceModel) beeswax.User {
 	return beeswax.User{
 		ID:               plan.ID.ValueInt64(),
@@ -196,7 +307,7 @@ func convertToUser(plan userResourceModel) beeswax.User {
 		FirstName:        plan.FirstName.ValueString(),
 		LastName:         plan.LastName.ValueString(),
 		RoleID:           plan.RoleID.ValueInt64(),
-		AccountID:        plan.AccountID.ValueInt64(),
+		AccountID:        plan.AccountID.ValueInt64Pointer(),
 		Active:           plan.Active.ValueBool(),
 		AllAccountAccess: plan.AllAccountAccess.ValueBool(),
 		AccountGroupIDs:  convertListInt(plan.AccountGroupIDs),
@@ -210,7 +321,7 @@ func fillStateFromUser(state *userResourceModel, user beeswax.User) {
 	state.FirstName = types.StringValue(user.FirstName)
 	state.LastName = types.StringValue(user.LastName)
 	state.RoleID = types.Int64Value(user.RoleID)
-	state.AccountID = types.Int64Value(user.AccountID)
+	state.AccountID = types.Int64PointerValue(user.AccountID)
 	state.Active = types.BoolValue(user.Active)
 	state.AllAccountAccess = types.BoolValue(user.AllAccountAccess)
 	g := []types.Int64{}
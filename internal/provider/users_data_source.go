@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &usersDataSource{}
+	_ datasource.DataSourceWithConfigure = &usersDataSource{}
+)
+
+type usersDataSource struct {
+	client *beeswax.Client
+}
+
+func NewUsersDataSource() datasource.DataSource {
+	return &usersDataSource{}
+}
+
+// usersResourceModel is the data the data source manipulates.
+type usersResourceModel struct {
+	RoleID      types.Int64         `tfsdk:"role_id"`
+	AccountID   types.Int64         `tfsdk:"account_id"`
+	Active      types.Bool          `tfsdk:"active"`
+	SuperUser   types.Bool          `tfsdk:"super_user"`
+	EmailDomain types.String        `tfsdk:"email_domain"`
+	Users       []userResourceModel `tfsdk:"users"`
+}
+
+func (d *usersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (r *usersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	r.client = defaultConfiguration(req.ProviderData, resp.Diagnostics)
+}
+
+func (d *usersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"role_id":      schema.Int64Attribute{Optional: true, Description: "Only keep users having this role."},
+			"account_id":   schema.Int64Attribute{Optional: true, Description: "Only keep users of this account."},
+			"active":       schema.BoolAttribute{Optional: true, Description: "Only keep active users when true, inactive users when false."},
+			"super_user":   schema.BoolAttribute{Optional: true, Description: "Only keep super users when true, regular users when false."},
+			"email_domain": schema.StringAttribute{Optional: true, Description: `Only keep users whose email belongs to this domain, e.g. "myorg.com".`},
+			"users": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "List of User available on Beeswax API",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: userDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *usersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Get current state
+	var state usersResourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Filters are sent to the API and checked again locally, the email domain only locally
+	filter := beeswax.UserFilter{
+		RoleID:    state.RoleID.ValueInt64(),
+		AccountID: state.AccountID.ValueInt64(),
+		Active:    state.Active.ValueBoolPointer(),
+		SuperUser: state.SuperUser.ValueBoolPointer(),
+	}
+	domainSuffix := "@" + strings.ToLower(strings.TrimPrefix(state.EmailDomain.ValueString(), "@"))
+
+	// Get users from Beeswax API
+	users, err := d.client.GetUsers(ctx, filter)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Beeswax User",
+			fmt.Sprintf("Could not read Beeswax users: %s", err.Error()),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state.Users = []userResourceModel{}
+	for _, user := range users {
+		if (filter.RoleID != 0 && user.RoleID != filter.RoleID) ||
+			(filter.AccountID != 0 && (user.AccountID == nil || *user.AccountID != filter.AccountID)) ||
+			(filter.Active != nil && user.Active != *filter.Active) ||
+			(filter.SuperUser != nil && user.SuperUser != *filter.SuperUser) ||
+			(!state.EmailDomain.IsNull() && !strings.HasSuffix(strings.ToLower(user.Email), domainSuffix)) {
+			continue
+		}
+		var model userResourceModel
+		fillStateFromUser(&model, user)
+		state.Users = append(state.Users, model)
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -1,9 +1,16 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
 )
@@ -30,3 +37,72 @@ func convertListInt(list []types.Int64) []int64 {
 	}
 	return result
 }
+
+// addClientError reports an error returned by the Beeswax client.
+// Field-level messages of an API error are attached to the matching attribute.
+func addClientError(diagnostics *diag.Diagnostics, summary, detail string, err error) {
+	var apiErr *beeswax.APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Errors) == 0 {
+		diagnostics.AddError(summary, detail+err.Error())
+		return
+	}
+	general := false
+	for _, e := range apiErr.Errors {
+		if e.Field == "" {
+			general = true
+			continue
+		}
+		attr, _, _ := strings.Cut(e.Field, ".")
+		diagnostics.AddAttributeError(path.Root(attr), summary, fmt.Sprintf("%s%s (API response %d on %s %s)", detail, e.Message, apiErr.StatusCode, apiErr.Method, apiErr.Path))
+	}
+	if general {
+		diagnostics.AddError(summary, detail+err.Error())
+	}
+}
+
+// removeIfNotFound drops the resource from the state when the API reports it no longer exists,
+// letting Terraform plan to create it again. It returns true when err was handled that way.
+func removeIfNotFound(ctx context.Context, err error, resp *resource.ReadResponse) bool {
+	if !beeswax.IsNotFound(err) {
+		return false
+	}
+	resp.State.RemoveResource(ctx)
+	return true
+}
+
+// changedFields returns the JSON fields of planned whose value differs from prior, for PATCH requests.
+// Fields only present in prior are returned with a nil value.
+// The ID is never part of the result.
+func changedFields(prior, planned interface{}) (map[string]interface{}, error) {
+	priorFields, err := jsonFields(prior)
+	if err != nil {
+		return nil, err
+	}
+	plannedFields, err := jsonFields(planned)
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]interface{}{}
+	for name, value := range plannedFields {
+		if name != "id" && !reflect.DeepEqual(priorFields[name], value) {
+			changed[name] = value
+		}
+	}
+	// Optional fields removed from the plan are omitted from its JSON, send them as null to unset them
+	for name := range priorFields {
+		if _, ok := plannedFields[name]; !ok && name != "id" {
+			changed[name] = nil
+		}
+	}
+	return changed, nil
+}
+
+func jsonFields(object interface{}) (map[string]interface{}, error) {
+	payload, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(payload, &fields)
+	return fields, err
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martin-magakian/terraform-provider-beeswax/internal/beeswax-client"
+)
+
+func TestChangedFields(t *testing.T) {
+	accountID := int64(7)
+	prior := beeswax.User{ID: 1, Email: "a@example.com", FirstName: "Martin", RoleID: 2, AccountID: &accountID, AccountGroupIDs: []int64{1, 2}}
+
+	tests := []struct {
+		name    string
+		planned beeswax.User
+		want    map[string]interface{}
+	}{
+		{
+			name:    "no change",
+			planned: prior,
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "changed fields only",
+			planned: beeswax.User{ID: 1, Email: "a@example.com", FirstName: "Martine", RoleID: 3, AccountID: &accountID, AccountGroupIDs: []int64{1, 2, 3}},
+			want:    map[string]interface{}{"first_name": "Martine", "role_id": float64(3), "account_group_ids": []interface{}{float64(1), float64(2), float64(3)}},
+		},
+		{
+			name:    "removed optional field",
+			planned: beeswax.User{ID: 1, Email: "a@example.com", FirstName: "Martin", RoleID: 2, AccountGroupIDs: []int64{1, 2}},
+			want:    map[string]interface{}{"account_id": nil},
+		},
+		{
+			name:    "ID is never sent",
+			planned: beeswax.User{ID: 9, Email: "a@example.com", FirstName: "Martin", RoleID: 2, AccountID: &accountID, AccountGroupIDs: []int64{1, 2}},
+			want:    map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		got, err := changedFields(prior, tt.planned)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: changedFields() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestChangedFieldsIgnoresPermissionOrder checks that roles holding the same permissions and reports
+// in a different order produce no update.
+func TestChangedFieldsIgnoresPermissionOrder(t *testing.T) {
+	role := func(permissions []permissionResourceModel, reportIDs ...int64) roleResourceModel {
+		return roleResourceModel{ID: types.Int64Value(1), Name: types.StringValue("my_role"), Permissions: permissions, ReportIDs: reportIDsValue(reportIDs)}
+	}
+	account, static := permissionModel(13), permissionModel(1)
+	account.ObjectType, static.ObjectType = types.StringValue("account"), types.StringValue("static")
+	prior := role([]permissionResourceModel{account, static}, 3, 1, 2)
+	planned := role([]permissionResourceModel{
+		{ObjectType: types.StringValue("static"), Permission: types.Int64Value(1)},
+		{ObjectType: types.StringValue("account"), Permission: types.Int64Unknown(), Read: types.BoolValue(true), Update: types.BoolValue(true), Delete: types.BoolValue(true)},
+	}, 2, 3, 1)
+
+	got, err := changedFields(convertToRole(prior), convertToRole(planned))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("changedFields() = %v, want no change", got)
+	}
+}